@@ -0,0 +1,413 @@
+// Package autodiscovery scans cmd/<profile>/ directories and generates the
+// wiring that turns them into profiles: one generated.go per profile and a
+// registry of every profile at the module root.
+package autodiscovery
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ikaitla/framework"
+)
+
+const cobraImportPath = "github.com/spf13/cobra"
+
+// Identifiers emitted into every generated.go; profiles must not declare them.
+const (
+	rootConstructorName = "NewRootCmd"
+	commandsVarName     = "Commands"
+	metadataVarName     = "Metadata"
+)
+
+// Config controls where the generator looks and what it emits.
+// Zero values are filled in by Generate and Scan.
+type Config struct {
+	// Root is the module root, the directory holding go.mod.
+	Root string
+	// CmdDir is the profiles directory, relative to Root.
+	CmdDir string
+	// ModulePath is the module path; read from go.mod when empty.
+	ModulePath string
+	// ProfileImportPath is the import path of the profile package.
+	ProfileImportPath string
+	// RegistryPackage is the package name of the registry file;
+	// detected from the Go files in Root, "main" if there are none.
+	RegistryPackage string
+}
+
+// Profile is a profile discovered under the profiles directory.
+type Profile struct {
+	// Dir is the profile directory name, e.g. "ikaitla".
+	Dir string
+	// Name is Metadata.Name when it is a string literal, Dir otherwise.
+	Name string
+	// Package is the Go package name declared by the profile's files.
+	Package string
+	// ImportPath is the full import path of the profile package.
+	ImportPath string
+	// Commands are the New<Name>Cmd constructors, sorted by name.
+	Commands []string
+}
+
+func (c *Config) setDefaults() error {
+	if c.Root == "" {
+		c.Root = "."
+	}
+	if c.CmdDir == "" {
+		c.CmdDir = framework.CmdProfilesImportBase
+	}
+	if c.ProfileImportPath == "" {
+		c.ProfileImportPath = framework.ProfilePackageImportPath
+	}
+	if c.ModulePath == "" {
+		mod, err := readModulePath(filepath.Join(c.Root, "go.mod"))
+		if err != nil {
+			return err
+		}
+		c.ModulePath = mod
+	}
+	if c.RegistryPackage == "" {
+		pkg, err := detectPackage(c.Root)
+		if err != nil {
+			return err
+		}
+		if pkg == "" {
+			pkg = "main"
+		}
+		c.RegistryPackage = pkg
+	}
+	return nil
+}
+
+// Scan discovers every profile under cfg.CmdDir. Directories without a
+// metadata.go are skipped; anything else that cannot be wired is an error.
+func Scan(cfg Config) ([]Profile, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	base := filepath.Join(cfg.Root, cfg.CmdDir)
+	entries, err := os.ReadDir(base)
+	if err != nil {
+		return nil, fmt.Errorf("autodiscovery: read profiles dir: %w", err)
+	}
+
+	var profiles []Profile
+	seen := map[string]string{}
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") || strings.HasPrefix(e.Name(), "_") {
+			continue
+		}
+		dir := filepath.Join(base, e.Name())
+		if _, err := os.Stat(filepath.Join(dir, framework.ProfileMetadataFile)); err != nil {
+			continue
+		}
+
+		p, err := scanProfile(cfg, dir, e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := seen[p.Name]; ok {
+			return nil, fmt.Errorf("autodiscovery: profile name %q declared by both %s and %s", p.Name, other, p.Dir)
+		}
+		seen[p.Name] = p.Dir
+		profiles = append(profiles, p)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Dir < profiles[j].Dir })
+	return profiles, nil
+}
+
+func scanProfile(cfg Config, dir, name string) (Profile, error) {
+	p := Profile{
+		Dir:        name,
+		Name:       name,
+		ImportPath: path.Join(cfg.ModulePath, filepath.ToSlash(cfg.CmdDir), name),
+	}
+
+	files, err := goFiles(dir)
+	if err != nil {
+		return p, err
+	}
+
+	fset := token.NewFileSet()
+	hasMetadata := false
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return p, fmt.Errorf("autodiscovery: %w", err)
+		}
+
+		if p.Package == "" {
+			p.Package = f.Name.Name
+		} else if p.Package != f.Name.Name {
+			return p, fmt.Errorf("autodiscovery: %s: package %s, expected %s", fset.Position(f.Package), f.Name.Name, p.Package)
+		}
+
+		if err := checkReserved(fset, f); err != nil {
+			return p, err
+		}
+
+		cobraName := importName(f, cobraImportPath)
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || !isCommandConstructor(fn, cobraName) {
+				continue
+			}
+			p.Commands = append(p.Commands, fn.Name.Name)
+		}
+
+		if file != framework.ProfileMetadataFile {
+			continue
+		}
+		metaName, found, err := findMetadata(f, importName(f, cfg.ProfileImportPath))
+		if err != nil {
+			return p, fmt.Errorf("autodiscovery: %s: %w", filepath.Join(dir, file), err)
+		}
+		if !found {
+			return p, fmt.Errorf("autodiscovery: %s: no %s variable of type profile.ProfileMetadata",
+				filepath.Join(dir, file), metadataVarName)
+		}
+		hasMetadata = true
+		if metaName != "" {
+			p.Name = metaName
+		}
+	}
+
+	if !hasMetadata {
+		return p, fmt.Errorf("autodiscovery: %s: missing %s", dir, framework.ProfileMetadataFile)
+	}
+
+	sort.Strings(p.Commands)
+	return p, nil
+}
+
+// goFiles lists the non-test, non-generated Go files of dir.
+func goFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("autodiscovery: %w", err)
+	}
+	var files []string
+	for _, e := range entries {
+		n := e.Name()
+		if e.IsDir() || !strings.HasSuffix(n, ".go") || strings.HasSuffix(n, "_test.go") {
+			continue
+		}
+		if n == framework.ProfileWiringOutputFile || n == framework.MainRegistryOutputFile {
+			continue
+		}
+		files = append(files, n)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// isCommandConstructor reports whether fn has the shape
+// func New<Name>Cmd() *cobra.Command.
+func isCommandConstructor(fn *ast.FuncDecl, cobraName string) bool {
+	if fn.Recv != nil || cobraName == "" {
+		return false
+	}
+	name := fn.Name.Name
+	if name == rootConstructorName || !strings.HasPrefix(name, "New") || !strings.HasSuffix(name, "Cmd") {
+		return false
+	}
+	if len(name) <= len("NewCmd") || !unicode.IsUpper(rune(name[len("New")])) {
+		return false
+	}
+	if fn.Type.TypeParams != nil || fn.Type.Params.NumFields() != 0 {
+		return false
+	}
+	if fn.Type.Results == nil || len(fn.Type.Results.List) != 1 || len(fn.Type.Results.List[0].Names) > 1 {
+		return false
+	}
+	star, ok := fn.Type.Results.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	return isSelector(star.X, cobraName, "Command")
+}
+
+// findMetadata locates the package-level Metadata variable and returns the
+// Name field when it is set with a string literal.
+func findMetadata(f *ast.File, profileName string) (string, bool, error) {
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, ident := range vs.Names {
+				if ident.Name != metadataVarName {
+					continue
+				}
+				if profileName == "" {
+					return "", false, fmt.Errorf("%s must be a profile.ProfileMetadata", metadataVarName)
+				}
+				var lit *ast.CompositeLit
+				if i < len(vs.Values) {
+					lit, _ = vs.Values[i].(*ast.CompositeLit)
+				}
+				typeOK := vs.Type != nil && isSelector(vs.Type, profileName, "ProfileMetadata")
+				if !typeOK && lit != nil {
+					typeOK = isSelector(lit.Type, profileName, "ProfileMetadata")
+				}
+				if !typeOK {
+					return "", false, fmt.Errorf("%s must be a profile.ProfileMetadata", metadataVarName)
+				}
+				return literalName(lit), true, nil
+			}
+		}
+	}
+	return "", false, nil
+}
+
+func literalName(lit *ast.CompositeLit) string {
+	if lit == nil {
+		return ""
+	}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok || key.Name != "Name" {
+			continue
+		}
+		bl, ok := kv.Value.(*ast.BasicLit)
+		if !ok || bl.Kind != token.STRING {
+			return ""
+		}
+		s, err := strconv.Unquote(bl.Value)
+		if err != nil {
+			return ""
+		}
+		return s
+	}
+	return ""
+}
+
+// checkReserved rejects package-level declarations that would collide with
+// the generated wiring.
+func checkReserved(fset *token.FileSet, f *ast.File) error {
+	reserved := func(name string, pos token.Pos) error {
+		if name == rootConstructorName || name == commandsVarName {
+			return fmt.Errorf("autodiscovery: %s: %s is reserved for generated code", fset.Position(pos), name)
+		}
+		return nil
+	}
+	for _, decl := range f.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				if err := reserved(d.Name.Name, d.Pos()); err != nil {
+					return err
+				}
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						if err := reserved(n.Name, n.Pos()); err != nil {
+							return err
+						}
+					}
+				case *ast.TypeSpec:
+					if err := reserved(s.Name.Name, s.Pos()); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// importName returns the name under which f imports importPath, or "" if it
+// does not import it.
+func importName(f *ast.File, importPath string) string {
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || p != importPath {
+			continue
+		}
+		if imp.Name != nil {
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				return ""
+			}
+			return imp.Name.Name
+		}
+		return path.Base(p)
+	}
+	return ""
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	return ok && id.Name == pkg && sel.Sel.Name == name
+}
+
+func readModulePath(gomod string) (string, error) {
+	f, err := os.Open(gomod)
+	if err != nil {
+		return "", fmt.Errorf("autodiscovery: %w", err)
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if !strings.HasPrefix(line, "module") {
+			continue
+		}
+		mod := strings.TrimSpace(strings.TrimPrefix(line, "module"))
+		if i := strings.Index(mod, "//"); i >= 0 {
+			mod = strings.TrimSpace(mod[:i])
+		}
+		if unq, err := strconv.Unquote(mod); err == nil {
+			mod = unq
+		}
+		if mod != "" {
+			return mod, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return "", fmt.Errorf("autodiscovery: %w", err)
+	}
+	return "", fmt.Errorf("autodiscovery: %s: no module directive", gomod)
+}
+
+// detectPackage returns the package name used by the Go files in dir.
+func detectPackage(dir string) (string, error) {
+	files, err := goFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, filepath.Join(dir, file), nil, parser.PackageClauseOnly)
+		if err != nil {
+			return "", fmt.Errorf("autodiscovery: %w", err)
+		}
+		return f.Name.Name, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,114 @@
+package autodiscovery_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/autodiscovery"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\ngo 1.24\n")
+	writeFile(t, filepath.Join(root, "cmd", "demo", "metadata.go"), `package demo
+
+import "github.com/ikaitla/framework/profile"
+
+var Metadata = profile.ProfileMetadata{Name: "demo-cli", Version: "1.0.0"}
+`)
+	writeFile(t, filepath.Join(root, "cmd", "demo", "commands.go"), `package demo
+
+import "github.com/spf13/cobra"
+
+func NewStatusCmd() *cobra.Command { return &cobra.Command{Use: "status"} }
+func NewDeployCmd() *cobra.Command { return &cobra.Command{Use: "deploy"} }
+func newHelperCmd() *cobra.Command { return nil }
+func NewWithArgsCmd(name string) *cobra.Command { return nil }
+`)
+	return root
+}
+
+func TestScan(t *testing.T) {
+	root := newModule(t)
+	writeFile(t, filepath.Join(root, "cmd", "notaprofile", "util.go"), "package notaprofile\n")
+
+	profiles, err := autodiscovery.Scan(autodiscovery.Config{Root: root})
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(profiles) != 1 {
+		t.Fatalf("expected 1 profile, got %d", len(profiles))
+	}
+	p := profiles[0]
+	if p.Name != "demo-cli" || p.Package != "demo" || p.ImportPath != "example.com/app/cmd/demo" {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+	if strings.Join(p.Commands, ",") != "NewDeployCmd,NewStatusCmd" {
+		t.Fatalf("unexpected commands: %v", p.Commands)
+	}
+}
+
+func TestScan_InvalidMetadata(t *testing.T) {
+	root := newModule(t)
+	writeFile(t, filepath.Join(root, "cmd", "demo", "metadata.go"), "package demo\n\nvar Metadata = 42\n")
+
+	if _, err := autodiscovery.Scan(autodiscovery.Config{Root: root}); err == nil {
+		t.Fatal("expected error for untyped Metadata")
+	}
+}
+
+func TestScan_ReservedName(t *testing.T) {
+	root := newModule(t)
+	writeFile(t, filepath.Join(root, "cmd", "demo", "root.go"), `package demo
+
+import "github.com/spf13/cobra"
+
+func NewRootCmd() *cobra.Command { return nil }
+`)
+
+	if _, err := autodiscovery.Scan(autodiscovery.Config{Root: root}); err == nil {
+		t.Fatal("expected error for hand-written NewRootCmd")
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	root := newModule(t)
+	writeFile(t, filepath.Join(root, "main.go"), "package main\n\nfunc main() {}\n")
+
+	if _, err := autodiscovery.Generate(autodiscovery.Config{Root: root}); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	wiring, err := os.ReadFile(filepath.Join(root, "cmd", "demo", "generated.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package demo", "NewDeployCmd,", "NewStatusCmd,", "func NewRootCmd() *cobra.Command"} {
+		if !strings.Contains(string(wiring), want) {
+			t.Errorf("generated.go missing %q:\n%s", want, wiring)
+		}
+	}
+
+	registry, err := os.ReadFile(filepath.Join(root, "generated_profiles.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package main", `demo "example.com/app/cmd/demo"`, "NewRoot: demo.NewRootCmd", "profile.RegisterProfiles(Profiles...)"} {
+		if !strings.Contains(string(registry), want) {
+			t.Errorf("generated_profiles.go missing %q:\n%s", want, registry)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Command autodiscovery generates profile wiring for an Ikaitla module.
+//
+// Add this directive next to main.go and run `go generate ./...`:
+//
+//	//go:generate go run github.com/ikaitla/framework/autodiscovery/cmd/autodiscovery
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ikaitla/framework/autodiscovery"
+)
+
+func main() {
+	var cfg autodiscovery.Config
+	flag.StringVar(&cfg.Root, "root", ".", "module root containing go.mod")
+	flag.StringVar(&cfg.CmdDir, "cmd", "", "profiles directory relative to -root (default \"cmd\")")
+	flag.StringVar(&cfg.ModulePath, "module", "", "module path (default: read from go.mod)")
+	flag.StringVar(&cfg.RegistryPackage, "package", "", "package name of the registry file (default: detected, or main)")
+	quiet := flag.Bool("q", false, "do not list generated profiles")
+	flag.Parse()
+
+	profiles, err := autodiscovery.Generate(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	if *quiet {
+		return
+	}
+	for _, p := range profiles {
+		fmt.Printf("%s: %d command(s)\n", p.Name, len(p.Commands))
+	}
+}
@@ -0,0 +1,180 @@
+package autodiscovery
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ikaitla/framework"
+)
+
+const generatedHeader = "// Code generated by " + framework.EngineName + " autodiscovery. DO NOT EDIT."
+
+var wiringTemplate = template.Must(template.New("wiring").Parse(generatedHeader + `
+
+package {{.Profile.Package}}
+
+import (
+	profile "{{.ProfileImportPath}}"
+	"` + cobraImportPath + `"
+)
+
+// Commands lists the command constructors discovered in {{.CmdDir}}/{{.Profile.Dir}}.
+var Commands = []func() *cobra.Command{
+{{- range .Profile.Commands}}
+	{{.}},
+{{- end}}
+}
+
+// NewRootCmd builds the {{.Profile.Name}} root command with every discovered command attached.
+func NewRootCmd() *cobra.Command {
+	root := profile.NewRootCommand(Metadata)
+	for _, newCmd := range Commands {
+		root.AddCommand(newCmd())
+	}
+	profile.AddSharedCommands(root)
+	return root
+}
+`))
+
+var registryTemplate = template.Must(template.New("registry").Parse(generatedHeader + `
+
+package {{.Package}}
+
+import (
+	profile "{{.ProfileImportPath}}"
+{{if .Imports}}
+{{- range .Imports}}
+	{{.Alias}} "{{.Path}}"
+{{- end}}
+{{end -}}
+)
+
+// Profiles lists every profile discovered under {{.CmdDir}}/.
+var Profiles = []profile.Definition{
+{{- range .Imports}}
+	{Metadata: {{.Alias}}.Metadata, NewRoot: {{.Alias}}.NewRootCmd, Commands: {{.Alias}}.Commands},
+{{- end}}
+}
+
+func init() {
+	profile.RegisterProfiles(Profiles...)
+}
+`))
+
+type registryImport struct {
+	Alias string
+	Path  string
+}
+
+// Generate scans cfg.CmdDir and writes the wiring file of every profile and
+// the registry file at cfg.Root.
+func Generate(cfg Config) ([]Profile, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	profiles, err := Scan(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range profiles {
+		src, err := RenderWiring(cfg, p)
+		if err != nil {
+			return nil, err
+		}
+		out := filepath.Join(cfg.Root, cfg.CmdDir, p.Dir, framework.ProfileWiringOutputFile)
+		if err := writeIfChanged(out, src); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := RenderRegistry(cfg, profiles)
+	if err != nil {
+		return nil, err
+	}
+	if err := writeIfChanged(filepath.Join(cfg.Root, framework.MainRegistryOutputFile), src); err != nil {
+		return nil, err
+	}
+
+	return profiles, nil
+}
+
+// RenderWiring returns the formatted generated.go source for p.
+func RenderWiring(cfg Config, p Profile) ([]byte, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+	return render(wiringTemplate, map[string]any{
+		"Profile":           p,
+		"ProfileImportPath": cfg.ProfileImportPath,
+		"CmdDir":            filepath.ToSlash(cfg.CmdDir),
+	})
+}
+
+// RenderRegistry returns the formatted generated_profiles.go source listing
+// profiles.
+func RenderRegistry(cfg Config, profiles []Profile) ([]byte, error) {
+	if err := cfg.setDefaults(); err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{"profile": true, "cobra": true, "main": true}
+	imports := make([]registryImport, 0, len(profiles))
+	for _, p := range profiles {
+		alias := identifier(p.Dir)
+		for base, i := alias, 2; used[alias]; i++ {
+			alias = fmt.Sprintf("%s%d", base, i)
+		}
+		used[alias] = true
+		imports = append(imports, registryImport{Alias: alias, Path: p.ImportPath})
+	}
+
+	return render(registryTemplate, map[string]any{
+		"Package":           cfg.RegistryPackage,
+		"ProfileImportPath": cfg.ProfileImportPath,
+		"CmdDir":            filepath.ToSlash(cfg.CmdDir),
+		"Imports":           imports,
+	})
+}
+
+func render(t *template.Template, data any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("autodiscovery: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("autodiscovery: format %s: %w", t.Name(), err)
+	}
+	return src, nil
+}
+
+func writeIfChanged(path string, src []byte) error {
+	if old, err := os.ReadFile(path); err == nil && bytes.Equal(old, src) {
+		return nil
+	}
+	if err := os.WriteFile(path, src, 0o644); err != nil {
+		return fmt.Errorf("autodiscovery: %w", err)
+	}
+	return nil
+}
+
+// identifier turns a profile directory name into a lower-case import alias.
+func identifier(name string) string {
+	name = strings.ToLower(strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return -1
+	}, name))
+	if name == "" || ('0' <= name[0] && name[0] <= '9') {
+		name = "p" + name
+	}
+	return name
+}
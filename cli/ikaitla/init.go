@@ -3,18 +3,59 @@ package ikaitla
 import (
 	"fmt"
 
+	"github.com/ikaitla/framework"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/theme"
 	"github.com/spf13/cobra"
 )
 
 func NewInitCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		s     Scaffold
+		color string
+		dir   string
+		force bool
+	)
+
+	cmd := &cobra.Command{
 		Use:   "init [profile-name]",
 		Short: "Initialize a new profile",
-		Args:  cobra.ExactArgs(1),
-		Run: func(cmd *cobra.Command, args []string) {
-			profileName := args[0]
-			fmt.Printf("Initializing profile: %s\n", profileName)
-			fmt.Println("Profile created successfully")
+		Long: fmt.Sprintf("Create %s/<profile-name>/ with a %s, a sample command and its test.",
+			framework.CmdProfilesImportBase, framework.ProfileMetadataFile),
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s.Name = args[0]
+			s.Color = theme.Token(color)
+			if s.Description == "" {
+				s.Description = fmt.Sprintf("%s CLI", s.Name)
+			}
+
+			if err := s.Validate(); err != nil {
+				return err
+			}
+
+			ui.Info("Initializing profile: %s", s.Name)
+			files, err := s.Write(dir, force)
+			if err != nil {
+				return err
+			}
+			for _, f := range files {
+				ui.Print("  created %s", f)
+			}
+			ui.Success("Profile %s created", s.Name)
+			ui.Print("Run `go generate ./...` to wire it, then `go build`.")
+			return nil
 		},
 	}
+
+	cmd.Flags().StringVar(&s.Author, "author", "", "Profile author")
+	cmd.Flags().StringVar(&s.Version, "version", "0.1.0", "Initial profile version")
+	cmd.Flags().StringVar(&s.Description, "description", "", "Short description (default \"<profile-name> CLI\")")
+	cmd.Flags().StringVar(&color, "color", "", "Brand color as a theme token, e.g. cyan-600")
+	cmd.Flags().StringSliceVar(&s.Aliases, "alias", nil, "Alternative binary names (repeatable)")
+	cmd.Flags().StringVar(&s.Command, "command", "hello", "Name of the sample command")
+	cmd.Flags().StringVar(&dir, "dir", framework.CmdProfilesImportBase, "Profiles directory")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing profile")
+
+	return cmd
 }
@@ -2,10 +2,25 @@ package ikaitla
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
 	"github.com/spf13/cobra"
 )
 
+// profileInfo is the listing entry for one registered profile
+type profileInfo struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Aliases     []string `json:"aliases"`
+	Author      string   `json:"author,omitempty"`
+	Hidden      bool     `json:"hidden"`
+	Commands    int      `json:"commands"`
+}
+
 func NewProfileCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "profile",
@@ -15,11 +30,51 @@ func NewProfileCmd() *cobra.Command {
 	cmd.AddCommand(&cobra.Command{
 		Use:   "list",
 		Short: "List all profiles",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("Available profiles:")
-			fmt.Println("  - ikaitla")
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			infos := listProfiles(profile.RegisteredProfiles())
+
+			if f := ui.Format(); f != output.Text && !f.Tabular() {
+				return ui.PrintValue(infos)
+			}
+
+			if len(infos) == 0 {
+				ui.Warning("No profiles registered")
+				return nil
+			}
+
+			table := ui.NewTable("Name", "Version", "Aliases", "Author", "Hidden", "Commands")
+			for _, p := range infos {
+				hidden := "no"
+				if p.Hidden {
+					hidden = "yes"
+				}
+				table.AddRow(p.Name, p.Version, strings.Join(p.Aliases, ", "), p.Author, hidden, fmt.Sprint(p.Commands))
+			}
+			table.Render()
+			return nil
 		},
 	})
 
 	return cmd
 }
+
+func listProfiles(defs []profile.Definition) []profileInfo {
+	infos := make([]profileInfo, 0, len(defs))
+	for _, def := range defs {
+		aliases := def.Metadata.Aliases
+		if aliases == nil {
+			aliases = []string{}
+		}
+		infos = append(infos, profileInfo{
+			Name:        def.Metadata.Name,
+			Version:     def.Metadata.Version,
+			Description: def.Metadata.Description,
+			Aliases:     aliases,
+			Author:      def.Metadata.Author,
+			Hidden:      def.Metadata.Hidden,
+			Commands:    len(def.Commands),
+		})
+	}
+	return infos
+}
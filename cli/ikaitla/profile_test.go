@@ -0,0 +1,99 @@
+package ikaitla_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/cli/ikaitla"
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+func TestProfileList(t *testing.T) {
+	newCmd := func() *cobra.Command { return &cobra.Command{Use: "status"} }
+	profile.RegisterProfiles(
+		profile.Definition{
+			Metadata: profile.ProfileMetadata{Name: "list-db", Version: "1.2.0", Aliases: []string{"ldb", "ld"}, Author: "Ada"},
+			NewRoot:  newCmd,
+			Commands: []func() *cobra.Command{newCmd, newCmd},
+		},
+		profile.Definition{
+			Metadata: profile.ProfileMetadata{Name: "list-ops", Version: "0.1.0", Hidden: true},
+			NewRoot:  newCmd,
+		},
+	)
+
+	var stdout bytes.Buffer
+	ui.SetOutput(&stdout, &bytes.Buffer{})
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+		ui.SetFormat(output.Text)
+	})
+
+	list := func(format output.Format) string {
+		t.Helper()
+		stdout.Reset()
+		ui.SetFormat(format)
+		cmd := ikaitla.NewProfileCmd()
+		cmd.SetArgs([]string{"list"})
+		if err := cmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		return stdout.String()
+	}
+
+	rows := map[string]string{}
+	for _, line := range strings.Split(list(output.Text), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			rows[fields[0]] = line
+		}
+	}
+	for name, want := range map[string][]string{
+		"list-db":  {"1.2.0", "ldb, ld", "Ada", "no", "2"},
+		"list-ops": {"0.1.0", "yes", "0"},
+	} {
+		row, ok := rows[name]
+		if !ok {
+			t.Fatalf("table has no %s row: %v", name, rows)
+		}
+		for _, w := range want {
+			if !strings.Contains(row, w) {
+				t.Errorf("%s row missing %q: %s", name, w, row)
+			}
+		}
+	}
+
+	var infos []struct {
+		Name     string   `json:"name"`
+		Version  string   `json:"version"`
+		Aliases  []string `json:"aliases"`
+		Hidden   bool     `json:"hidden"`
+		Commands int      `json:"commands"`
+	}
+	if err := json.Unmarshal([]byte(list(output.JSON)), &infos); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	got := map[string]int{}
+	for i, info := range infos {
+		got[info.Name] = i
+	}
+	db, ok := got["list-db"]
+	if !ok || infos[db].Version != "1.2.0" || len(infos[db].Aliases) != 2 || infos[db].Hidden || infos[db].Commands != 2 {
+		t.Errorf("list-db = %+v", infos)
+	}
+	ops, ok := got["list-ops"]
+	if !ok || len(infos[ops].Aliases) != 0 || !infos[ops].Hidden || infos[ops].Commands != 0 {
+		t.Errorf("list-ops = %+v, want hidden with no aliases and no commands", infos)
+	}
+	if !strings.Contains(list(output.JSON), `"aliases": []`) {
+		t.Error("a profile without aliases should list an empty array, not null")
+	}
+}
@@ -0,0 +1,214 @@
+package ikaitla
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ikaitla/framework"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Scaffold describes a profile directory to create
+type Scaffold struct {
+	Name        string
+	Author      string
+	Version     string
+	Description string
+	Color       theme.Token
+	Aliases     []string
+	Command     string
+}
+
+// Package returns the Go package name used for the profile directory
+func (s Scaffold) Package() string {
+	return strings.ReplaceAll(s.Name, "-", "")
+}
+
+// Validate rejects names and tokens that would not produce a working profile
+func (s Scaffold) Validate() error {
+	if !profileNamePattern.MatchString(s.Name) {
+		return fmt.Errorf("invalid profile name %q: use lower-case letters, digits and dashes", s.Name)
+	}
+	if token.IsKeyword(s.Package()) {
+		return fmt.Errorf("invalid profile name %q: %q is a Go keyword", s.Name, s.Package())
+	}
+	for _, a := range s.Aliases {
+		if !profileNamePattern.MatchString(a) {
+			return fmt.Errorf("invalid alias %q: use lower-case letters, digits and dashes", a)
+		}
+	}
+	if s.Color != "" && !theme.Known(s.Color) {
+		return fmt.Errorf("unknown brand color %q (expected a theme token such as cyan-600)", s.Color)
+	}
+	if !profileNamePattern.MatchString(s.Command) {
+		return fmt.Errorf("invalid command name %q", s.Command)
+	}
+	return nil
+}
+
+// Write renders the profile files into dir/<name>. Existing files are only
+// replaced when force is set.
+func (s Scaffold) Write(dir string, force bool) ([]string, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	profileDir := filepath.Join(dir, s.Name)
+	files := []struct {
+		name string
+		tmpl *template.Template
+	}{
+		{framework.ProfileMetadataFile, metadataTemplate},
+		{s.Command + ".go", commandTemplate},
+		{s.Command + "_test.go", commandTestTemplate},
+	}
+
+	if !force {
+		for _, f := range files {
+			if _, err := os.Stat(filepath.Join(profileDir, f.name)); err == nil {
+				return nil, fmt.Errorf("profile %q already exists in %s (use --force to overwrite)", s.Name, profileDir)
+			}
+		}
+	}
+
+	rendered := make([][]byte, len(files))
+	for i, f := range files {
+		src, err := s.render(f.tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("render %s: %w", f.name, err)
+		}
+		rendered[i] = src
+	}
+
+	if err := os.MkdirAll(profileDir, 0o755); err != nil {
+		return nil, err
+	}
+	created := make([]string, 0, len(files))
+	for i, f := range files {
+		path := filepath.Join(profileDir, f.name)
+		if err := os.WriteFile(path, rendered[i], 0o644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	return created, nil
+}
+
+func (s Scaffold) render(t *template.Template) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, s); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// ColorConst returns the theme constant name for the brand color,
+// e.g. "cyan-600" -> "Cyan600"
+func (s Scaffold) ColorConst() string {
+	return exportedName(string(s.Color))
+}
+
+// CommandFunc returns the constructor name of the sample command
+func (s Scaffold) CommandFunc() string {
+	return "New" + exportedName(s.Command) + "Cmd"
+}
+
+// exportedName turns a dashed name into CamelCase, e.g. "db-sync" -> "DbSync"
+func exportedName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+var scaffoldFuncs = template.FuncMap{"quote": strconv.Quote}
+
+var metadataTemplate = template.Must(template.New("metadata").Funcs(scaffoldFuncs).Parse(`package {{.Package}}
+
+import (
+	"github.com/ikaitla/framework/profile"
+{{- if .Color}}
+	"github.com/ikaitla/framework/ui/theme"
+{{- end}}
+)
+
+var Metadata = profile.ProfileMetadata{
+	Name:        {{quote .Name}},
+	Version:     {{quote .Version}},
+	Description: {{quote .Description}},
+{{- if .Author}}
+	Author:      {{quote .Author}},
+{{- end}}
+{{- if .Aliases}}
+	Aliases:     []string{ {{- range $i, $a := .Aliases}}{{if $i}}, {{end}}{{quote $a}}{{end -}} },
+{{- end}}
+{{- if .Color}}
+	Brand: profile.Brand{
+		Name:  {{quote .Name}},
+		Color: theme.{{.ColorConst}},
+	},
+{{- end}}
+}
+`))
+
+var commandTemplate = template.Must(template.New("command").Funcs(scaffoldFuncs).Parse(`package {{.Package}}
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// {{.CommandFunc}} is picked up by autodiscovery; rename or replace it with a real command.
+func {{.CommandFunc}}() *cobra.Command {
+	return &cobra.Command{
+		Use:   "{{.Command}} [name]",
+		Short: "Say hello",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "world"
+			if len(args) == 1 {
+				name = args[0]
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Hello, %s!\n", name)
+			return nil
+		},
+	}
+}
+`))
+
+var commandTestTemplate = template.Must(template.New("command_test").Funcs(scaffoldFuncs).Parse(`package {{.Package}}
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test{{.CommandFunc}}(t *testing.T) {
+	cmd := {{.CommandFunc}}()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{ {{- quote .Name -}} })
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if got, want := out.String(), {{printf "Hello, %s!\n" .Name | quote}}; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+`))
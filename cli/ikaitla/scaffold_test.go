@@ -0,0 +1,67 @@
+package ikaitla_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/cli/ikaitla"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+func TestScaffoldWrite(t *testing.T) {
+	dir := t.TempDir()
+	s := ikaitla.Scaffold{
+		Name:    "db-sync",
+		Version: "0.1.0",
+		Color:   theme.Cyan600,
+		Aliases: []string{"ds"},
+		Command: "status",
+	}
+
+	files, err := s.Write(dir, false)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("expected 3 files, got %v", files)
+	}
+
+	meta, err := os.ReadFile(filepath.Join(dir, "db-sync", "metadata.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"package dbsync", `Name:        "db-sync"`, "theme.Cyan600", `[]string{"ds"}`} {
+		if !strings.Contains(string(meta), want) {
+			t.Errorf("metadata.go missing %q:\n%s", want, meta)
+		}
+	}
+
+	cmd, err := os.ReadFile(filepath.Join(dir, "db-sync", "status.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(cmd), "func NewStatusCmd() *cobra.Command") {
+		t.Errorf("status.go missing constructor:\n%s", cmd)
+	}
+
+	if _, err := s.Write(dir, false); err == nil {
+		t.Error("expected refusal to overwrite without force")
+	}
+	if _, err := s.Write(dir, true); err != nil {
+		t.Errorf("Write with force: %v", err)
+	}
+}
+
+func TestScaffoldValidate(t *testing.T) {
+	for _, s := range []ikaitla.Scaffold{
+		{Name: "Bad_Name", Command: "hello"},
+		{Name: "func", Command: "hello"},
+		{Name: "ok", Command: "hello", Color: "blue-9"},
+	} {
+		if err := s.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", s)
+		}
+	}
+}
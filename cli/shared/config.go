@@ -0,0 +1,228 @@
+package shared
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/ikaitla/framework/config"
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/theme"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	profile.RegisterSharedCommand(NewConfigCmd())
+}
+
+// NewConfigCmd manages the profile's configuration file
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "View and edit profile settings",
+		Long: "View and edit the profile's settings. A setting given as a flag wins over\n" +
+			"its environment variable, which wins over the configuration file.",
+	}
+	// config must still run to repair a file every other command rejects
+	profile.AllowBrokenConfig(cmd)
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List effective settings and where they come from",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				renderValues(cfg.Values(cmd.InheritedFlags())...)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "get <key>",
+			Short: "Show the effective value of a setting",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				v, ok := cfg.Get(cmd.InheritedFlags(), args[0])
+				if !ok {
+					return profile.UsageError("%s is not set", args[0]).
+						WithHint("Run '%s config list' to see the settings.", cmd.Root().Name())
+				}
+				if ui.Format() != output.Text {
+					return ui.PrintValue(v)
+				}
+				renderValues(v)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "set <key> <value>",
+			Short: "Set a value in the configuration file",
+			Args:  cobra.ExactArgs(2),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := writableConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := profile.ValidateSetting(cmd, args[0], args[1]); err != nil {
+					return err
+				}
+				if err := cfg.SetArg(cmd.InheritedFlags(), args[0], args[1]); err != nil {
+					return profile.WrapError(profile.CategoryUsage, err)
+				}
+				if err := cfg.Save(); err != nil {
+					return profile.WrapError(profile.CategoryConfig, err)
+				}
+				ui.Success("Set %s in %s", args[0], cfg.Path)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "unset <key>",
+			Short: "Remove a value from the configuration file",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := writableConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if !cfg.Unset(args[0]) {
+					return profile.UsageError("%s is not set in %s", args[0], cfg.Path)
+				}
+				if err := cfg.Save(); err != nil {
+					return profile.WrapError(profile.CategoryConfig, err)
+				}
+				ui.Success("Removed %s from %s", args[0], cfg.Path)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "path",
+			Short: "Show the configuration file path",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if ui.Format() != output.Text {
+					return ui.PrintValue(map[string]string{"path": cfg.Path})
+				}
+				ui.Print("%s", cfg.Path)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "edit",
+			Short: "Open the configuration file in $VISUAL or $EDITOR",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+					return profile.WrapError(profile.CategoryConfig, err)
+				}
+
+				argv := append(editorCommand(), cfg.Path)
+				editor := exec.CommandContext(cmd.Context(), argv[0], argv[1:]...)
+				editor.Stdin, editor.Stdout, editor.Stderr = os.Stdin, os.Stdout, os.Stderr
+				if err := editor.Run(); err != nil {
+					return profile.ConfigError("editor failed: %w", err).
+						WithHint("Set $EDITOR to the editor to use.")
+				}
+
+				meta, _ := profile.MetadataOf(cmd)
+				if _, err := config.Load(meta.Name, cfg.Path); err != nil {
+					return profile.WrapError(profile.CategoryConfig, err).
+						WithHint("Run '%s config edit' again to fix it.", cmd.Root().Name())
+				}
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
+
+// loadConfig returns the configuration of the running profile, warning on
+// stderr about the settings that could not be applied
+func loadConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := profile.ConfigOf(cmd)
+	if cfg == nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, profile.InternalError("%s has no profile configuration", cmd.Root().Name())
+	}
+	if cfg.Path == "" {
+		return nil, profile.ConfigError("no configuration directory").
+			WithHint("Set XDG_CONFIG_HOME or HOME.")
+	}
+	if skipped := profile.ConfigErrorOf(cmd); skipped != nil {
+		for _, line := range strings.Split(skipped.Error(), "\n") {
+			ui.Banner(theme.Warning600, "%s", line)
+		}
+	}
+	return cfg, nil
+}
+
+// writableConfig is loadConfig for commands that save the file, which must
+// not replace a file that could not be read or parsed
+func writableConfig(cmd *cobra.Command) (*config.Config, error) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := profile.ConfigOf(cmd); err != nil {
+		return nil, profile.ConfigError("%s cannot be loaded; not saving over it", cfg.Path).
+			WithHint("Run '%s config edit' to fix it.", cmd.Root().Name())
+	}
+	return cfg, nil
+}
+
+// renderValues prints settings as "key: value (source)", or as objects in
+// structured formats
+func renderValues(values ...config.Value) {
+	if ui.Format() != output.Text {
+		if err := ui.PrintValue(values); err != nil {
+			ui.Error("%v", err)
+		}
+		return
+	}
+
+	pairs := make(map[string]string, len(values))
+	for _, v := range values {
+		source := string(v.Source)
+		if v.Origin != "" {
+			source += " " + v.Origin
+		}
+		pairs[v.Key] = v.Value + " " + ui.Colorize("("+source+")", theme.Slate500)
+	}
+	ui.RenderKeyValue(pairs)
+}
+
+// editorCommand returns $VISUAL, $EDITOR or the platform's default editor,
+// split into arguments so "code --wait" works
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if editor := strings.Fields(os.Getenv(name)); len(editor) > 0 {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
@@ -0,0 +1,114 @@
+package shared
+
+import (
+	"os"
+
+	"github.com/ikaitla/framework/config"
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	profile.RegisterSharedCommand(NewContextCmd())
+}
+
+// NewContextCmd switches between the named contexts of the profile
+// configuration, such as dev, staging and prod
+func NewContextCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "context",
+		Short: "Switch between named contexts",
+		Long: "Switch between the named contexts of the profile configuration. The\n" +
+			"values of the active context override the top-level ones; --context\n" +
+			"selects one for a single command.",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List contexts",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				contexts := cfg.Contexts()
+
+				if f := ui.Format(); f != output.Text && !f.Tabular() {
+					return ui.PrintValue(contexts)
+				}
+
+				if len(contexts) == 0 {
+					ui.Warning("No contexts defined in %s", cfg.Path)
+					return nil
+				}
+
+				table := ui.NewTable("Current", "Name", "Dangerous")
+				for _, c := range contexts {
+					current, dangerous := "", "no"
+					if c.Current {
+						current = "*"
+					}
+					if c.Dangerous {
+						dangerous = "yes"
+					}
+					table.AddRow(current, c.Name, dangerous)
+				}
+				table.Render()
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "current",
+			Short: "Show the active context",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				ctx, ok := cfg.CurrentContext()
+				if !ok {
+					return profile.ConfigError("no context is selected").
+						WithHint("Run '%s context use <name>' to select one.", cmd.Root().Name())
+				}
+
+				if ui.Format() != output.Text {
+					return ui.PrintValue(ctx)
+				}
+				ui.Print("%s", ctx.Name)
+				return nil
+			},
+		},
+		&cobra.Command{
+			Use:   "use <name>",
+			Short: "Make a context the default",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					return err
+				}
+				name := args[0]
+				if !cfg.HasContext(name) {
+					return profile.UsageError("unknown context %q", name).WithHint("%s", profile.ContextHint(cfg))
+				}
+
+				cfg.Set(config.ContextKey, name)
+				if err := cfg.Save(); err != nil {
+					return profile.WrapError(profile.CategoryConfig, err)
+				}
+				ui.Success("Switched to context %s", name)
+				if env := cfg.EnvVar(config.ContextKey); os.Getenv(env) != "" {
+					ui.Warning("%s is set and takes precedence", env)
+				}
+				return nil
+			},
+		},
+	)
+
+	return cmd
+}
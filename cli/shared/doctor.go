@@ -1,24 +1,196 @@
 package shared
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
+	"os"
 	"runtime"
+	"strings"
+	"time"
 
+	"github.com/ikaitla/framework/internal/sysinfo"
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/theme"
 	"github.com/spf13/cobra"
 )
 
+// doctorReport is the structured output of doctor
+type doctorReport struct {
+	Status profile.CheckStatus   `json:"status"`
+	System sysinfo.Info          `json:"system"`
+	Checks []profile.CheckReport `json:"checks"`
+}
+
+var checkStatusTokens = map[profile.CheckStatus]theme.Token{
+	profile.CheckOK:   theme.Success600,
+	profile.CheckWarn: theme.Warning600,
+	profile.CheckFail: theme.Danger600,
+}
+
+func init() {
+	profile.RegisterSharedCommand(NewDoctorCmd())
+}
+
+// NewDoctorCmd runs the health checks registered with profile.RegisterCheck
 func NewDoctorCmd() *cobra.Command {
-	return &cobra.Command{
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
 		Use:   "doctor",
 		Short: "Check system health",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Println("System Health Check")
-			fmt.Println("===================")
-			fmt.Printf("Go version: %s\n", runtime.Version())
-			fmt.Printf("OS: %s\n", runtime.GOOS)
-			fmt.Printf("Arch: %s\n", runtime.GOARCH)
-			fmt.Printf("CPUs: %d\n", runtime.NumCPU())
-			fmt.Println("\nAll systems operational")
+		Long: "Run the health checks registered by the profile concurrently and show how\n" +
+			"to fix the problems found. Exits with status 1 when a check fails.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			checks := append([]profile.Check{configCheck(cmd)}, profile.RegisteredChecks()...)
+			system := sysinfo.Collect()
+			text := ui.Format() == output.Text
+
+			var spinner *ui.Spinner
+			if text {
+				pairs := make(map[string]string)
+				for _, p := range system.Pairs() {
+					pairs[p[0]] = p[1]
+				}
+				ui.RenderKeyValue(pairs)
+				ui.Print("")
+
+				spinner = ui.NewSpinner("Running " + checkCount(len(checks)))
+				spinner.Watch(cmd.Context())
+				spinner.Start()
+			}
+
+			finished := 0
+			reports := profile.RunChecks(cmd.Context(), checks, timeout, func(profile.CheckReport) {
+				finished++
+				if spinner != nil {
+					spinner.UpdateMessage(fmt.Sprintf("Running checks (%d/%d)", finished, len(checks)))
+				}
+			})
+			if err := cmd.Context().Err(); err != nil {
+				return err
+			}
+
+			status, failed, warned := summarize(reports)
+			if !text {
+				if ui.Format().Tabular() {
+					if err := ui.PrintValue(reports); err != nil {
+						return err
+					}
+				} else if err := ui.PrintValue(doctorReport{Status: status, System: system, Checks: reports}); err != nil {
+					return err
+				}
+			} else {
+				spinner.UpdateMessage("Ran " + checkCount(len(checks)))
+				spinner.Stop(failed == 0)
+				renderChecks(reports)
+			}
+
+			switch {
+			case failed > 0:
+				// the report says which checks failed, so nothing else is printed
+				// after it but the text summary line
+				if text {
+					ui.Error("%d of %s failed", failed, checkCount(len(checks)))
+				}
+				return profile.NewError(profile.CategoryCheck, "%d of %s failed", failed, checkCount(len(checks))).Silence()
+			case !text:
+			case warned > 0:
+				ui.Warning("%d of %s passed with warnings", warned, checkCount(len(checks)))
+			default:
+				ui.Success("%s passed", checkCount(len(checks)))
+			}
+			return nil
+		},
+	}
+	// doctor must still run to diagnose a configuration every other command
+	// rejects; configCheck reports what is wrong with it
+	profile.AllowBrokenConfig(cmd)
+	cmd.Flags().DurationVar(&timeout, "timeout", profile.DefaultCheckTimeout, "Time limit for each check")
+
+	return cmd
+}
+
+// renderChecks prints the summary table, then how to fix each problem
+func renderChecks(reports []profile.CheckReport) {
+	table := ui.NewTable("Status", "Check", "Message", "Duration")
+	for _, r := range reports {
+		table.AddRow(string(r.Status), r.Name, r.Message, r.Duration.Round(time.Millisecond).String()).
+			StyleCell(0, checkStatusTokens[r.Status])
+	}
+	table.Render()
+
+	for _, r := range reports {
+		if r.Status != profile.CheckOK && r.Remediation != "" {
+			ui.Info("%s: %s", r.Name, r.Remediation)
+		}
+	}
+}
+
+// checkCount returns "1 check" or "n checks"
+func checkCount(n int) string {
+	if n == 1 {
+		return "1 check"
+	}
+	return fmt.Sprintf("%d checks", n)
+}
+
+// summarize returns the worst status and the number of failures and warnings
+func summarize(reports []profile.CheckReport) (status profile.CheckStatus, failed, warned int) {
+	for _, r := range reports {
+		switch r.Status {
+		case profile.CheckFail:
+			failed++
+		case profile.CheckWarn:
+			warned++
+		}
+	}
+	switch {
+	case failed > 0:
+		return profile.CheckFail, failed, warned
+	case warned > 0:
+		return profile.CheckWarn, failed, warned
+	}
+	return profile.CheckOK, failed, warned
+}
+
+// configCheck verifies the profile configuration loads, holds valid
+// settings and is private to the user, since it may hold credentials
+func configCheck(cmd *cobra.Command) profile.Check {
+	return profile.Check{
+		Name: "config",
+		Run: func(ctx context.Context) profile.CheckResult {
+			cfg, err := profile.ConfigOf(cmd)
+			if skipped := profile.ConfigErrorOf(cmd); skipped != nil {
+				err = skipped
+			}
+			if err != nil {
+				hint := fmt.Sprintf("Run '%s config edit' to fix the configuration file, or remove it.", cmd.Root().Name())
+				return profile.CheckFailed(hint, "%s", strings.ReplaceAll(err.Error(), "\n", "; "))
+			}
+			if cfg == nil || cfg.Path == "" {
+				return profile.CheckWarning("Set XDG_CONFIG_HOME or HOME.", "no configuration directory")
+			}
+
+			fi, err := os.Stat(cfg.Path)
+			if errors.Is(err, fs.ErrNotExist) {
+				return profile.CheckPassed("no configuration file, using defaults")
+			}
+			if err != nil {
+				return profile.CheckFailed("Check the permissions of the configuration directory.", "%v", err)
+			}
+			if runtime.GOOS != "windows" && fi.Mode().Perm()&0o077 != 0 {
+				return profile.CheckWarning(fmt.Sprintf("Run 'chmod 600 %s'.", cfg.Path),
+					"%s is readable by other users", cfg.Path)
+			}
+			if c, ok := cfg.CurrentContext(); ok {
+				return profile.CheckPassed("%s, context %s", cfg.Path, c.Name)
+			}
+			return profile.CheckPassed("%s", cfg.Path)
 		},
 	}
 }
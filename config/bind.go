@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Bind fills every flag of cmd that was not given on the command line from
+// the environment or the file, leaving the rest at their default. Call it
+// after cobra parsed the flags, e.g. from PersistentPreRunE.
+//
+// A flag is looked up under the command's own key first, so "deploy.region"
+// (or MY_TOOL_DEPLOY_REGION) configures --region of "my-tool deploy" only,
+// and then under its bare name, "region". Flag.Changed stays false for
+// bound flags, so it keeps meaning "given on the command line". Every
+// invalid value is reported, not only the first.
+func (c *Config) Bind(cmd *cobra.Command) error {
+	scope := commandKey(cmd)
+
+	var errs []error
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			c.bound[f.Name] = binding{source: SourceFlag}
+			return
+		}
+
+		keys := []string{f.Name}
+		if scope != "" {
+			keys = []string{scope + "." + f.Name, f.Name}
+		}
+		for _, key := range keys {
+			value, source, origin, ok := c.lookup(key)
+			if !ok {
+				continue
+			}
+			if err := f.Value.Set(value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value %q for --%s from %s %s: %w", value, f.Name, source, origin, err))
+				return
+			}
+			c.bound[f.Name] = binding{source: source, origin: origin}
+			return
+		}
+		c.bound[f.Name] = binding{source: SourceDefault}
+	})
+
+	return errors.Join(errs...)
+}
+
+// Value is an effective setting and where it came from.
+type Value struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+	Origin string `json:"origin,omitempty"`
+}
+
+// Values returns the effective value of every flag in flags, typically the
+// root's persistent flags, followed by the file keys and active context
+// keys that no flag reads, sorted by key. Other contexts are left out. An
+// environment variable shows up as the source of the key it configures;
+// one that matches no known key is not listed, since its name alone does
+// not tell "deploy.region" from "deploy-region". Call it after Bind.
+func (c *Config) Values(flags *pflag.FlagSet) []Value {
+	seen := map[string]bool{}
+	var values []Value
+	add := func(v Value) {
+		if !seen[v.Key] {
+			seen[v.Key] = true
+			values = append(values, v)
+		}
+	}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		add(Value{Key: f.Name, Value: f.Value.String(), Source: c.Source(f.Name), Origin: c.Origin(f.Name)})
+	})
+	for _, key := range c.Keys() {
+		if rest, ok := strings.CutPrefix(key, ContextsKey+"."); ok {
+			if c.context == "" || !strings.HasPrefix(rest, c.context+".") {
+				continue
+			}
+			key = strings.TrimPrefix(rest, c.context+".")
+		}
+		value, source, origin, _ := c.lookup(key)
+		add(Value{Key: key, Value: value, Source: source, Origin: origin})
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i].Key < values[j].Key })
+	return values
+}
+
+// Get returns the effective value of key: the flag of that name in flags
+// when there is one, else the environment or file value.
+func (c *Config) Get(flags *pflag.FlagSet, key string) (Value, bool) {
+	if f := flags.Lookup(key); f != nil {
+		return Value{Key: key, Value: f.Value.String(), Source: c.Source(key), Origin: c.Origin(key)}, true
+	}
+	value, source, origin, ok := c.lookup(key)
+	if !ok {
+		return Value{}, false
+	}
+	return Value{Key: key, Value: value, Source: source, Origin: origin}, true
+}
+
+// SetArg stores raw, a value typed on the command line, under key. It is
+// kept as a string unless flags has a bool, number or slice flag of that
+// name, in which case the flag's own parsing decides the stored type, so
+// "007" or "1.10" are never mangled into numbers.
+func (c *Config) SetArg(flags *pflag.FlagSet, key, raw string) error {
+	value := any(raw)
+	if f := flags.Lookup(key); f != nil {
+		var err error
+		if value, err = parseFlagValue(f.Value.Type(), raw); err != nil {
+			return fmt.Errorf("invalid value %q for --%s: %w", raw, key, err)
+		}
+	}
+	c.Set(key, value)
+	return nil
+}
+
+// parseFlagValue parses raw with a scratch pflag flag of the given type;
+// types it does not know are stored as strings.
+func parseFlagValue(typ, raw string) (any, error) {
+	const name = "value"
+	fs := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	switch typ {
+	case "bool":
+		fs.Bool(name, false, "")
+	case "int", "count":
+		fs.Int(name, 0, "")
+	case "float64":
+		fs.Float64(name, 0, "")
+	case "stringSlice", "stringArray":
+		fs.StringSlice(name, nil, "")
+	case "intSlice":
+		fs.IntSlice(name, nil, "")
+	default:
+		return raw, nil
+	}
+	if err := fs.Set(name, raw); err != nil {
+		return nil, err
+	}
+
+	switch typ {
+	case "bool":
+		return fs.GetBool(name)
+	case "int", "count":
+		return fs.GetInt(name)
+	case "float64":
+		return fs.GetFloat64(name)
+	case "intSlice":
+		ints, err := fs.GetIntSlice(name)
+		list := make([]any, len(ints))
+		for i, n := range ints {
+			list[i] = n
+		}
+		return list, err
+	default:
+		strs, err := fs.GetStringSlice(name)
+		list := make([]any, len(strs))
+		for i, s := range strs {
+			list[i] = s
+		}
+		return list, err
+	}
+}
+
+// commandKey returns the path of cmd below the root joined by dots, e.g.
+// "cluster.create"; it is empty for the root itself.
+func commandKey(cmd *cobra.Command) string {
+	var names []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	return strings.Join(names, ".")
+}
@@ -0,0 +1,260 @@
+// Package config loads a profile's configuration file and environment
+// variables and binds them into cobra flags, so a flag given on the command
+// line wins over the environment, which wins over the active context of the
+// file, then the rest of the file, then the flag's default.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Source tells where a value came from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceContext Source = "context"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// FileName is the base name of the file under the profile's config directory.
+const FileName = "config"
+
+// extensions are tried in order after the bare path; a file without one is
+// read as YAML, which also covers JSON.
+var extensions = []string{".yaml", ".yml", ".json", ".toml"}
+
+// Config holds a profile's file values and reads its environment variables.
+type Config struct {
+	// Path is the configuration file, whether or not it exists.
+	Path string
+
+	// EnvPrefix starts every environment variable of the profile, e.g.
+	// "MY_TOOL_" for the profile "my-tool".
+	EnvPrefix string
+
+	values  map[string]any
+	bound   map[string]binding
+	context string
+}
+
+// binding records where Bind took a flag's value from.
+type binding struct {
+	source Source
+	origin string
+}
+
+// Load reads the configuration of profile. path is the profile's
+// ConfigPath; when empty, DefaultPath is used. A missing file is not an
+// error and yields a Config backed by the environment only. A file that
+// cannot be read or parsed returns the error along with such a Config, so
+// its Path is still known, e.g. to open it in an editor.
+func Load(profile, path string) (*Config, error) {
+	if path == "" {
+		path = DefaultPath(profile)
+	} else {
+		path = resolve(expandHome(path))
+	}
+
+	c := &Config{
+		Path:      path,
+		EnvPrefix: EnvPrefix(profile),
+		values:    map[string]any{},
+		bound:     map[string]binding{},
+	}
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, fmt.Errorf("read config: %w", err)
+	}
+
+	raw, err := decode(path, data)
+	if err != nil {
+		return c, fmt.Errorf("parse %s: %w", path, err)
+	}
+	flatten("", raw, c.values)
+	return c, nil
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/<profile>/config, falling back to the
+// platform's user config directory. An existing config.yaml, config.yml,
+// config.json or config.toml is preferred over the bare name. It returns ""
+// when no config directory is known.
+func DefaultPath(profile string) string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		var err error
+		if dir, err = os.UserConfigDir(); err != nil {
+			return ""
+		}
+	}
+	return resolve(filepath.Join(dir, profile, FileName))
+}
+
+// EnvPrefix returns the environment variable prefix of profile: its name in
+// upper case with anything but letters and digits turned into underscores.
+func EnvPrefix(profile string) string {
+	return envName(profile) + "_"
+}
+
+// EnvVar returns the environment variable read for key, e.g. MY_TOOL_LOG_LEVEL
+// for "log-level" or MY_TOOL_DEPLOY_REGION for "deploy.region".
+func (c *Config) EnvVar(key string) string {
+	return c.EnvPrefix + envName(key)
+}
+
+// Lookup returns the value of key from the environment or, failing that,
+// the active context or the rest of the file. Lists in the file are joined
+// with commas, as slice flags expect.
+func (c *Config) Lookup(key string) (string, Source, bool) {
+	value, source, _, ok := c.lookup(key)
+	return value, source, ok
+}
+
+// Keys returns the keys set in the file, sorted, with nested tables joined
+// by dots.
+func (c *Config) Keys() []string {
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Source returns where Bind took the named flag's value from, or
+// SourceDefault for a flag it did not see.
+func (c *Config) Source(flag string) Source {
+	if b, ok := c.bound[flag]; ok {
+		return b.source
+	}
+	return SourceDefault
+}
+
+// Origin names the environment variable, context or file a bound flag came
+// from; it is empty for flags set on the command line or left at their
+// default.
+func (c *Config) Origin(flag string) string {
+	return c.bound[flag].origin
+}
+
+// lookup also returns the origin of the value: the environment variable,
+// the context name or the file path.
+func (c *Config) lookup(key string) (value string, source Source, origin string, ok bool) {
+	name := c.EnvVar(key)
+	if v, ok := os.LookupEnv(name); ok {
+		return v, SourceEnv, name, true
+	}
+	if c.context != "" {
+		if v, ok := c.values[ContextsKey+"."+c.context+"."+strings.ToLower(key)]; ok {
+			return valueString(v), SourceContext, c.context, true
+		}
+	}
+	if v, ok := c.values[strings.ToLower(key)]; ok {
+		return valueString(v), SourceFile, c.Path, true
+	}
+	return "", "", "", false
+}
+
+// resolve returns path, or the first existing path with a known extension
+// appended when path itself does not exist.
+func resolve(path string) string {
+	if _, err := os.Stat(path); err == nil || filepath.Ext(path) != "" {
+		return path
+	}
+	for _, ext := range extensions {
+		if _, err := os.Stat(path + ext); err == nil {
+			return path + ext
+		}
+	}
+	return path
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// decode parses data according to the extension of path.
+func decode(path string, data []byte) (map[string]any, error) {
+	raw := map[string]any{}
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		err = yaml.Unmarshal(data, &raw)
+	}
+	return raw, err
+}
+
+// flatten stores the leaves of m in out under lower-cased dotted keys. An
+// empty table is kept so that an empty context still exists.
+func flatten(prefix string, m map[string]any, out map[string]any) {
+	for k, v := range m {
+		key := strings.ToLower(k)
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flatten(key, nested, out)
+			continue
+		}
+		out[key] = v
+	}
+}
+
+// valueString formats a file value the way a flag would be given it.
+func valueString(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		parts := make([]string, len(v))
+		for i, e := range v {
+			parts[i] = valueString(e)
+		}
+		return strings.Join(parts, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func envName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
@@ -0,0 +1,311 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadFormats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"config.yaml": "output: json\ndeploy:\n  region: eu\ntags: [a, b]\n",
+		"config.json": `{"output": "json", "deploy": {"region": "eu"}, "tags": ["a", "b"]}`,
+		"config.toml": "output = \"json\"\ntags = [\"a\", \"b\"]\n[deploy]\nregion = \"eu\"\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, content)
+
+		cfg, err := config.Load("demo", path)
+		if err != nil {
+			t.Fatalf("%s: Load: %v", name, err)
+		}
+		for key, want := range map[string]string{"output": "json", "deploy.region": "eu", "tags": "a,b"} {
+			if got, src, ok := cfg.Lookup(key); !ok || got != want || src != config.SourceFile {
+				t.Errorf("%s: Lookup(%q) = %q, %q, %v; want %q from file", name, key, got, src, ok, want)
+			}
+		}
+	}
+
+	if _, err := config.Load("demo", filepath.Join(dir, "config.json")+".broken"); err != nil {
+		t.Errorf("missing file should not be an error: %v", err)
+	}
+	writeFile(t, filepath.Join(dir, "bad.yaml"), "output: [json\n")
+	if cfg, err := config.Load("demo", filepath.Join(dir, "bad.yaml")); err == nil {
+		t.Error("expected a parse error")
+	} else if cfg == nil || cfg.Path != filepath.Join(dir, "bad.yaml") {
+		t.Errorf("a parse error should still return the path, got %+v", cfg)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if got, want := config.DefaultPath("my-tool"), filepath.Join(dir, "my-tool", "config"); got != want {
+		t.Errorf("DefaultPath = %q, want %q", got, want)
+	}
+	writeFile(t, filepath.Join(dir, "my-tool", "config.toml"), "")
+	if got, want := config.DefaultPath("my-tool"), filepath.Join(dir, "my-tool", "config.toml"); got != want {
+		t.Errorf("DefaultPath = %q, want %q", got, want)
+	}
+	if got := config.EnvPrefix("my-tool"); got != "MY_TOOL_" {
+		t.Errorf("EnvPrefix = %q", got)
+	}
+}
+
+func TestBindPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "output: yaml\nregion: us\ncolumns: [name, size]\ndeploy:\n  region: eu\n")
+	t.Setenv("MY_TOOL_OUTPUT", "json")
+	t.Setenv("MY_TOOL_COLUMNS", "id")
+
+	cfg, err := config.Load("my-tool", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output, region, level string
+	var columns []string
+	root := &cobra.Command{Use: "my-tool"}
+	root.PersistentFlags().StringVar(&output, "output", "text", "")
+	root.PersistentFlags().StringVar(&level, "log-level", "info", "")
+	root.PersistentFlags().StringSliceVar(&columns, "columns", nil, "")
+	deploy := &cobra.Command{
+		Use:  "deploy",
+		RunE: func(cmd *cobra.Command, _ []string) error { return cfg.Bind(cmd) },
+	}
+	deploy.Flags().StringVar(&region, "region", "", "")
+	root.AddCommand(deploy)
+
+	root.SetArgs([]string{"deploy", "--columns", "name"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flag, got, want string
+		source          config.Source
+	}{
+		{"output", output, "json", config.SourceEnv},
+		{"region", region, "eu", config.SourceFile},
+		{"log-level", level, "info", config.SourceDefault},
+		{"columns", columns[0], "name", config.SourceFlag},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want || cfg.Source(tt.flag) != tt.source {
+			t.Errorf("--%s = %q from %s, want %q from %s", tt.flag, tt.got, cfg.Source(tt.flag), tt.want, tt.source)
+		}
+	}
+	if len(columns) != 1 {
+		t.Errorf("columns = %v, want [name]", columns)
+	}
+	if got := cfg.Origin("output"); got != "MY_TOOL_OUTPUT" {
+		t.Errorf("Origin(output) = %q", got)
+	}
+}
+
+func TestSetSave(t *testing.T) {
+	for _, name := range []string{"config", "config.json", "config.toml"} {
+		path := filepath.Join(t.TempDir(), "demo", name)
+		cfg, err := config.Load("demo", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Set("deploy", "old")
+		cfg.Set("deploy.region", "eu")
+		cfg.Set("retries", 3)
+		cfg.Set("tags", []any{"a", "b"})
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("%s: Save: %v", name, err)
+		}
+
+		cfg, err = config.Load("demo", path)
+		if err != nil {
+			t.Fatalf("%s: reload: %v", name, err)
+		}
+		if got := cfg.Keys(); len(got) != 3 {
+			t.Errorf("%s: keys = %v, want deploy.region, retries, tags", name, got)
+		}
+		for key, want := range map[string]string{"deploy.region": "eu", "retries": "3", "tags": "a,b"} {
+			if got, _, _ := cfg.Lookup(key); got != want {
+				t.Errorf("%s: %s = %q, want %q", name, key, got, want)
+			}
+		}
+
+		if !cfg.Unset("deploy") || cfg.Unset("missing") {
+			t.Errorf("%s: Unset reported the wrong result", name)
+		}
+		if _, _, ok := cfg.Lookup("deploy.region"); ok {
+			t.Errorf("%s: deploy.region still set after Unset(deploy)", name)
+		}
+	}
+}
+
+func TestContexts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "context: dev\nregion: us\ncontexts:\n  dev: {}\n  prod:\n    region: eu\n    dangerous: true\n")
+	cfg, err := config.Load("demo", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := cfg.ContextNames(); len(got) != 2 || got[0] != "dev" || got[1] != "prod" {
+		t.Fatalf("ContextNames = %v, want [dev prod]", got)
+	}
+	if err := cfg.UseContext("staging"); err == nil {
+		t.Error("expected an error for an unknown context")
+	}
+
+	if err := cfg.UseContext("dev"); err != nil {
+		t.Fatal(err)
+	}
+	if got, src, _ := cfg.Lookup("region"); got != "us" || src != config.SourceFile {
+		t.Errorf("dev: region = %q from %s, want us from file", got, src)
+	}
+
+	if err := cfg.UseContext("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if got, src, _ := cfg.Lookup("region"); got != "eu" || src != config.SourceContext {
+		t.Errorf("prod: region = %q from %s, want eu from context", got, src)
+	}
+	if ctx, ok := cfg.CurrentContext(); !ok || ctx.Name != "prod" || !ctx.Dangerous {
+		t.Errorf("CurrentContext = %+v, %v", ctx, ok)
+	}
+	t.Setenv("DEMO_REGION", "ap")
+	if got, src, _ := cfg.Lookup("region"); got != "ap" || src != config.SourceEnv {
+		t.Errorf("env: region = %q from %s, want ap from env", got, src)
+	}
+}
+
+func TestSetArgKeepsStrings(t *testing.T) {
+	flags := pflag.NewFlagSet("demo", pflag.ContinueOnError)
+	flags.Bool("no-color", false, "")
+	flags.Int("retries", 0, "")
+	flags.StringSlice("columns", nil, "")
+	flags.String("output", "", "")
+
+	for _, name := range []string{"config", "config.json", "config.toml"} {
+		path := filepath.Join(t.TempDir(), name)
+		cfg, err := config.Load("demo", path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		args := map[string]string{
+			"version":  "1.10",
+			"id":       "007",
+			"token":    "0x1F",
+			"enabled":  "true",
+			"no-color": "true",
+			"retries":  "007",
+			"columns":  "name,size",
+			"output":   "1.0",
+		}
+		for key, raw := range args {
+			if err := cfg.SetArg(flags, key, raw); err != nil {
+				t.Fatalf("%s: SetArg(%s): %v", name, key, err)
+			}
+		}
+		if err := cfg.SetArg(flags, "retries", "many"); err == nil {
+			t.Errorf("%s: expected an error for a non-numeric --retries", name)
+		}
+		if err := cfg.Save(); err != nil {
+			t.Fatalf("%s: Save: %v", name, err)
+		}
+
+		cfg, err = config.Load("demo", path)
+		if err != nil {
+			t.Fatalf("%s: reload: %v", name, err)
+		}
+		want := map[string]string{
+			"version":  "1.10",
+			"id":       "007",
+			"token":    "0x1F",
+			"enabled":  "true",
+			"no-color": "true",
+			"retries":  "7",
+			"columns":  "name,size",
+			"output":   "1.0",
+		}
+		for key, w := range want {
+			if got, _, _ := cfg.Lookup(key); got != w {
+				t.Errorf("%s: %s = %q, want %q", name, key, got, w)
+			}
+		}
+	}
+}
+
+func TestValuesMapsEnvToKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, "deploy:\n  region: eu\n")
+	t.Setenv("DEMO_DEPLOY_REGION", "ap")
+	t.Setenv("DEMO_OUTPUT", "json")
+	t.Setenv("DEMO_STRAY_SETTING", "x")
+
+	cfg, err := config.Load("demo", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	flags := pflag.NewFlagSet("demo", pflag.ContinueOnError)
+	flags.String("output", "text", "")
+	root := &cobra.Command{Use: "demo"}
+	root.Flags().AddFlagSet(flags)
+	if err := cfg.Bind(root); err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]config.Value{}
+	for _, v := range cfg.Values(flags) {
+		got[v.Key] = v
+	}
+	if len(got) != 2 {
+		t.Errorf("Values = %v, want deploy.region and output", got)
+	}
+	for key, want := range map[string]config.Value{
+		"deploy.region": {Key: "deploy.region", Value: "ap", Source: config.SourceEnv, Origin: "DEMO_DEPLOY_REGION"},
+		"output":        {Key: "output", Value: "json", Source: config.SourceEnv, Origin: "DEMO_OUTPUT"},
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %+v, want %+v", key, got[key], want)
+		}
+	}
+}
+
+func TestBindReportsEveryInvalidValue(t *testing.T) {
+	t.Setenv("DEMO_RETRIES", "many")
+	t.Setenv("DEMO_VERBOSE", "loud")
+	cfg, err := config.Load("demo", filepath.Join(t.TempDir(), "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := &cobra.Command{Use: "demo"}
+	root.Flags().Int("retries", 0, "")
+	root.Flags().Bool("verbose", false, "")
+
+	err = cfg.Bind(root)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"--retries", "--verbose", "DEMO_RETRIES", "DEMO_VERBOSE"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Keys of the file that hold named contexts:
+//
+//	context: staging
+//	contexts:
+//	  staging:
+//	    api-url: https://staging.example.com
+//	  prod:
+//	    api-url: https://example.com
+//	    dangerous: true
+//	    color: red-600
+//
+// The values of the active context override the top-level ones.
+const (
+	ContextKey  = "context"
+	ContextsKey = "contexts"
+)
+
+// Context describes a named context of the file.
+type Context struct {
+	Name      string `json:"name"`
+	Current   bool   `json:"current"`
+	Dangerous bool   `json:"dangerous"`
+
+	// Color is the theme token of the dangerous-context banner, if any.
+	Color string `json:"color,omitempty"`
+}
+
+// Contexts returns the contexts defined in the file, sorted by name.
+func (c *Config) Contexts() []Context {
+	names := map[string]bool{}
+	for key := range c.values {
+		if rest, ok := strings.CutPrefix(key, ContextsKey+"."); ok {
+			name, _, _ := strings.Cut(rest, ".")
+			names[name] = true
+		}
+	}
+
+	contexts := make([]Context, 0, len(names))
+	for name := range names {
+		contexts = append(contexts, c.describe(name))
+	}
+	sort.Slice(contexts, func(i, j int) bool { return contexts[i].Name < contexts[j].Name })
+	return contexts
+}
+
+// ContextNames returns the names of the contexts, sorted.
+func (c *Config) ContextNames() []string {
+	contexts := c.Contexts()
+	names := make([]string, len(contexts))
+	for i, ctx := range contexts {
+		names[i] = ctx.Name
+	}
+	return names
+}
+
+// UseContext makes name the active context for lookups and Bind; an empty
+// name uses none. It does not change the file, see Set(ContextKey, name).
+func (c *Config) UseContext(name string) error {
+	if name != "" && !c.HasContext(name) {
+		return fmt.Errorf("unknown context %q", name)
+	}
+	c.context = strings.ToLower(name)
+	return nil
+}
+
+// HasContext reports whether the file defines the named context.
+func (c *Config) HasContext(name string) bool {
+	prefix := ContextsKey + "." + strings.ToLower(name)
+	for key := range c.values {
+		if key == prefix || strings.HasPrefix(key, prefix+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// CurrentContext returns the active context; ok is false when none is.
+func (c *Config) CurrentContext() (ctx Context, ok bool) {
+	if c.context == "" {
+		return Context{}, false
+	}
+	return c.describe(c.context), true
+}
+
+func (c *Config) describe(name string) Context {
+	prefix := ContextsKey + "." + name + "."
+	dangerous, _ := strconv.ParseBool(valueString(c.values[prefix+"dangerous"]))
+	return Context{
+		Name:      name,
+		Current:   name == c.context,
+		Dangerous: dangerous,
+		Color:     valueString(c.values[prefix+"color"]),
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Set stores value under key in the file values; Save writes them out.
+// The value is kept as given, so a string stays a string; see SetArg for
+// command-line input. Setting "deploy.region" replaces a scalar "deploy",
+// and the other way round.
+func (c *Config) Set(key string, value any) {
+	key = strings.ToLower(key)
+	for k := range c.values {
+		if strings.HasPrefix(k, key+".") || strings.HasPrefix(key, k+".") {
+			delete(c.values, k)
+		}
+	}
+	c.values[key] = value
+}
+
+// Unset removes key, or every key below it, from the file values and
+// reports whether anything was removed.
+func (c *Config) Unset(key string) bool {
+	key = strings.ToLower(key)
+	removed := false
+	for k := range c.values {
+		if k == key || strings.HasPrefix(k, key+".") {
+			delete(c.values, k)
+			removed = true
+		}
+	}
+	return removed
+}
+
+// Save writes the file values to Path in the format of its extension,
+// creating the directory if needed. A new file is only readable by the
+// user since it may hold credentials. Comments in the file are not kept.
+func (c *Config) Save() error {
+	if c.Path == "" {
+		return errors.New("no configuration directory")
+	}
+	data, err := encode(c.Path, unflatten(c.values))
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", c.Path, err)
+	}
+
+	mode := fs.FileMode(0o600)
+	if fi, err := os.Stat(c.Path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(c.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(c.Path, data, mode)
+}
+
+// encode is the inverse of decode.
+func encode(path string, v map[string]any) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		return append(data, '\n'), err
+	case ".toml":
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	default:
+		if len(v) == 0 {
+			return nil, nil
+		}
+		return yaml.Marshal(v)
+	}
+}
+
+// unflatten rebuilds nested tables from dotted keys.
+func unflatten(values map[string]any) map[string]any {
+	out := map[string]any{}
+	for key, v := range values {
+		m := out
+		parts := strings.Split(key, ".")
+		for _, p := range parts[:len(parts)-1] {
+			next, ok := m[p].(map[string]any)
+			if !ok {
+				next = map[string]any{}
+				m[p] = next
+			}
+			m = next
+		}
+		m[parts[len(parts)-1]] = v
+	}
+	return out
+}
@@ -6,10 +6,11 @@ const (
 	EngineVersion = "2026.02.04"
 	EngineTagline = "Multi-profile CLI framework with auto-discovery"
 
+	ProfileMetadataFile     = "metadata.go"
 	ProfileWiringOutputFile = "generated.go"
 	MainRegistryOutputFile  = "generated_profiles.go"
 
-	RepoModulePath             = ""
-	ProfilePackageImportPath   = ""
-	CmdProfilesImportBase      = ""
+	RepoModulePath           = "github.com/ikaitla/framework"
+	ProfilePackageImportPath = RepoModulePath + "/profile"
+	CmdProfilesImportBase    = "cmd"
 )
@@ -0,0 +1,36 @@
+// Package sysinfo collects the runtime details shown by doctor and written
+// to crash reports.
+package sysinfo
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// Info describes the Go runtime and platform the binary runs on.
+type Info struct {
+	GoVersion string `json:"go"`
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	CPUs      int    `json:"cpus"`
+}
+
+// Collect returns the current runtime details.
+func Collect() Info {
+	return Info{
+		GoVersion: runtime.Version(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+	}
+}
+
+// Pairs returns the details as labelled values, in display order.
+func (i Info) Pairs() [][2]string {
+	return [][2]string{
+		{"Go version", i.GoVersion},
+		{"OS", i.OS},
+		{"Arch", i.Arch},
+		{"CPUs", strconv.Itoa(i.CPUs)},
+	}
+}
@@ -0,0 +1,218 @@
+package profile
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ikaitla/framework/config"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/theme"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// rootConfigs caches the configuration loaded for each root command
+var rootConfigs sync.Map
+
+// loadedConfig is a rootConfigs entry: a file that fails to load keeps its
+// error, so every command of the run sees the same one
+type loadedConfig struct {
+	cfg *config.Config
+	err error
+}
+
+// brokenConfigAnnotation marks commands that run despite an invalid
+// configuration
+const brokenConfigAnnotation = "ikaitla_allow_broken_config"
+
+// skippedConfigs holds, per root, the configuration errors ApplyGlobalFlags
+// let a command marked with AllowBrokenConfig run past
+var skippedConfigs sync.Map
+
+// ConfigOf returns the configuration of the profile cmd belongs to, loading
+// it from ProfileMetadata.ConfigPath on first use. It returns nil for a
+// root not built by NewRootCommand. When the file cannot be read or parsed,
+// the error comes with a Config holding its path and the environment only.
+func ConfigOf(cmd *cobra.Command) (*config.Config, error) {
+	root := cmd.Root()
+	if loaded, ok := rootConfigs.Load(root); ok {
+		l := loaded.(loadedConfig)
+		return l.cfg, l.err
+	}
+	meta, ok := MetadataOf(root)
+	if !ok {
+		return nil, nil
+	}
+
+	cfg, err := config.Load(meta.Name, meta.ConfigPath)
+	l := loadedConfig{cfg: cfg}
+	if err != nil {
+		l.err = WrapError(CategoryConfig, err).WithHint("Fix or remove the configuration file.")
+	}
+	actual, _ := rootConfigs.LoadOrStore(root, l)
+	l = actual.(loadedConfig)
+	return l.cfg, l.err
+}
+
+// AllowBrokenConfig lets cmd and its subcommands run when the configuration
+// file cannot be loaded or holds invalid settings, e.g. to repair it.
+// ApplyGlobalFlags then skips the faulty settings instead of failing, and
+// ConfigErrorOf tells the command what was skipped.
+func AllowBrokenConfig(cmd *cobra.Command) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[brokenConfigAnnotation] = "true"
+}
+
+// allowsBrokenConfig reports whether cmd or one of its parents was marked
+// with AllowBrokenConfig
+func allowsBrokenConfig(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Annotations[brokenConfigAnnotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// ConfigErrorOf returns the configuration errors ApplyGlobalFlags skipped
+// for cmd, a command marked with AllowBrokenConfig, or nil
+func ConfigErrorOf(cmd *cobra.Command) error {
+	if err, ok := skippedConfigs.Load(cmd.Root()); ok {
+		return err.(error)
+	}
+	return nil
+}
+
+// bindConfig fills the flags of cmd not given on the command line from the
+// profile's environment variables and configuration file. Errors skip
+// accepts are left out of the returned error.
+func bindConfig(cmd *cobra.Command, skip func(*Error) bool) error {
+	cfg, err := ConfigOf(cmd)
+	if err != nil {
+		if e, ok := err.(*Error); !ok || !skip(e) {
+			return err
+		}
+	}
+	if cfg == nil {
+		return nil
+	}
+	if e := useContext(cmd, cfg); e != nil && !skip(e) {
+		return e
+	}
+	if err := cfg.Bind(cmd); err != nil {
+		if e := WrapError(CategoryConfig, err); !skip(e) {
+			return e
+		}
+	}
+	return nil
+}
+
+// ValidateSetting checks value for the setting key, also inside a context,
+// the way ApplyGlobalFlags checks the global flags, so a value that would
+// break every later run is refused before it is saved
+func ValidateSetting(cmd *cobra.Command, key, value string) error {
+	name := key
+	if rest, ok := strings.CutPrefix(key, config.ContextsKey+"."); ok {
+		_, name, _ = strings.Cut(rest, ".")
+	}
+
+	var err error
+	switch name {
+	case OutputFlag:
+		err = output.New().SetFormatSpec(value)
+	case LogLevelFlag:
+		if value != "" {
+			_, err = output.ParseLevel(value)
+		}
+	case QueryFlag:
+		if value != "" {
+			_, err = output.ParseQuery(value)
+		}
+	case config.ContextKey:
+		if cfg, _ := ConfigOf(cmd); cfg != nil && key == name && value != "" && !cfg.HasContext(value) {
+			return UsageError("invalid %s: unknown context %q", key, value).WithHint("%s", ContextHint(cfg))
+		}
+	}
+	if err != nil {
+		return UsageError("invalid %s: %w", key, err)
+	}
+	return nil
+}
+
+// useContext activates the context named by --context, or else by the
+// environment or the file, before the other flags are bound from it
+func useContext(cmd *cobra.Command, cfg *config.Config) *Error {
+	f := cmd.Flags().Lookup(ContextFlag)
+	if f == nil {
+		return nil
+	}
+
+	name, source := f.Value.String(), config.SourceFlag
+	if !f.Changed {
+		name, source, _ = cfg.Lookup(config.ContextKey)
+	}
+	err := cfg.UseContext(name)
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if source == config.SourceFlag {
+		e = UsageError("invalid --%s: %w", ContextFlag, err)
+	} else {
+		e = ConfigError("invalid %s from %s: %w", ContextFlag, source, err)
+	}
+	return e.WithHint("%s", ContextHint(cfg))
+}
+
+// ContextHint lists the contexts available in cfg, for error hints
+func ContextHint(cfg *config.Config) string {
+	names := cfg.ContextNames()
+	if len(names) == 0 {
+		return fmt.Sprintf("No contexts are defined in %s.", cfg.Path)
+	}
+	return fmt.Sprintf("Available contexts: %s.", strings.Join(names, ", "))
+}
+
+// warnDangerousContext prints a banner on stderr when the active context is
+// marked dangerous, in the context's color or else Danger600
+func warnDangerousContext(cmd *cobra.Command) {
+	cfg, _ := ConfigOf(cmd)
+	if cfg == nil {
+		return
+	}
+	ctx, ok := cfg.CurrentContext()
+	if !ok || !ctx.Dangerous {
+		return
+	}
+	token := theme.Danger600
+	if t := theme.Token(ctx.Color); ctx.Color != "" && theme.Known(t) {
+		token = t
+	}
+	ui.Banner(token, "Context %s is marked dangerous", ctx.Name)
+}
+
+// flagSet reports whether f was given on the command line or bound from the
+// environment, a context or the configuration file
+func flagSet(cmd *cobra.Command, f *pflag.Flag) bool {
+	if f.Changed {
+		return true
+	}
+	cfg, _ := ConfigOf(cmd)
+	return cfg != nil && cfg.Source(f.Name) != config.SourceDefault
+}
+
+// flagError reports an invalid flag value, as a config error when the value
+// came from the environment, a context or the configuration file
+func flagError(cmd *cobra.Command, name string, err error) *Error {
+	if cfg, _ := ConfigOf(cmd); cfg != nil {
+		if src := cfg.Source(name); src != config.SourceFlag && src != config.SourceDefault {
+			return ConfigError("invalid %s from %s %s: %w", name, src, cfg.Origin(name), err)
+		}
+	}
+	return UsageError("invalid --%s: %w", name, err)
+}
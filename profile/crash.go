@@ -0,0 +1,222 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ikaitla/framework/internal/sysinfo"
+	"github.com/ikaitla/framework/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// redacted replaces argument and flag values in crash reports
+const redacted = "[REDACTED]"
+
+// crashSafeAnnotation marks flags whose values may appear in crash reports
+const crashSafeAnnotation = "ikaitla_crash_safe"
+
+// crashSafeFlags are the global flags whose values never hold secrets
+var crashSafeFlags = map[string]bool{
+	OutputFlag: true, VerboseFlag: true, LogLevelFlag: true, NoColorFlag: true,
+	ColumnsFlag: true, FieldsFlag: true, ContextFlag: true, ProfileFlag: true,
+	"help": true, "version": true,
+}
+
+// MarkFlagSafe lets the value of the named flag appear in crash reports.
+// Every other flag value and positional argument is redacted.
+func MarkFlagSafe(flags *pflag.FlagSet, name string) error {
+	return flags.SetAnnotation(name, crashSafeAnnotation, []string{"true"})
+}
+
+// recoverPanic turns a panic in a command into a themed message and a crash
+// report file. It returns the exit code to use.
+func recoverPanic(root *cobra.Command, value any, stack []byte) int {
+	meta, _ := MetadataOf(root)
+	name := meta.Name
+	if name == "" {
+		name = root.Name()
+	}
+
+	f := ui.Failure{
+		Message:  fmt.Sprintf("%s crashed unexpectedly: %v", name, value),
+		Category: string(CategoryInternal),
+		Code:     ExitInternal,
+	}
+
+	path, err := writeCrashReport(name, crashReport(meta, name, redactArgs(root, os.Args), value, stack))
+	switch {
+	case err != nil:
+		f.Hint = fmt.Sprintf("The crash report could not be saved: %v", err)
+	case meta.IssuesURL != "":
+		f.Hint = fmt.Sprintf("Please report it at %s and attach %s", meta.IssuesURL, path)
+	default:
+		f.Hint = fmt.Sprintf("A crash report was saved to %s", path)
+	}
+	ui.Fail(f)
+	return f.Code
+}
+
+// crashReport renders the report attached to issues; args must already
+// be redacted
+func crashReport(meta ProfileMetadata, name string, args []string, value any, stack []byte) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s crash report\n\n", name)
+
+	pairs := [][2]string{
+		{"Time", time.Now().Format(time.RFC3339)},
+		{"Version", meta.Version},
+		{"Command", strings.Join(args, " ")},
+		{"Panic", fmt.Sprint(value)},
+	}
+	pairs = append(pairs, sysinfo.Collect().Pairs()...)
+	width := 0
+	for _, p := range pairs {
+		width = max(width, len(p[0]))
+	}
+	for _, p := range pairs {
+		fmt.Fprintf(&b, "%-*s  %s\n", width+1, p[0]+":", p[1])
+	}
+
+	fmt.Fprintf(&b, "\nStack:\n%s", stack)
+	return b.String()
+}
+
+// writeCrashReport saves report under the user cache directory, falling
+// back to the temp directory, and returns its path
+func writeCrashReport(name, report string) (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	dir := filepath.Join(base, name, "crash")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	file := fmt.Sprintf("crash-%s-%d.log", time.Now().Format("20060102-150405"), os.Getpid())
+	path := filepath.Join(dir, file)
+	if err := os.WriteFile(path, []byte(report), 0o600); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// redactArgs keeps the binary, the command names and the flag names of
+// args and hides everything else: positional arguments and flag values,
+// except for flags marked safe. Flags are resolved against the command
+// tree of root, so boolean flags do not swallow the next argument and
+// shorthands such as -p secret are caught.
+func redactArgs(root *cobra.Command, args []string) []string {
+	out := make([]string, len(args))
+	copy(out, args)
+
+	cmd := root
+	positional := false
+	for i := 1; i < len(out); i++ {
+		arg := out[i]
+		switch {
+		case arg == "--":
+			for j := i + 1; j < len(out); j++ {
+				out[j] = redacted
+			}
+			return out
+
+		case strings.HasPrefix(arg, "--"):
+			name, _, hasValue := strings.Cut(arg[2:], "=")
+			f := lookupFlag(cmd, name, "")
+			switch {
+			case hasValue:
+				if !isSafeFlag(f) {
+					out[i] = arg[:strings.Index(arg, "=")+1] + redacted
+				}
+			case takesValue(f) && i+1 < len(out):
+				i++
+				if !isSafeFlag(f) {
+					out[i] = redacted
+				}
+			}
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// -abc is a run of shorthands; the first one taking a value
+			// takes the rest of the argument, or the next one
+			for j := 1; j < len(arg); j++ {
+				f := lookupFlag(cmd, "", arg[j:j+1])
+				if f == nil {
+					// the rest may be the value of an unknown flag
+					if j+1 < len(arg) {
+						out[i] = arg[:j+1] + redacted
+					}
+					break
+				}
+				if !takesValue(f) {
+					continue
+				}
+				if j+1 < len(arg) {
+					if !isSafeFlag(f) {
+						out[i] = arg[:j+1] + redacted
+					}
+				} else if i+1 < len(out) {
+					i++
+					if !isSafeFlag(f) {
+						out[i] = redacted
+					}
+				}
+				break
+			}
+
+		default:
+			if sub := subcommand(cmd, arg); sub != nil && !positional {
+				cmd = sub
+				continue
+			}
+			positional = true
+			out[i] = redacted
+		}
+	}
+	return out
+}
+
+// lookupFlag finds a flag of cmd or its parents by name or shorthand
+func lookupFlag(cmd *cobra.Command, name, shorthand string) *pflag.Flag {
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.InheritedFlags()} {
+		if name != "" {
+			if f := flags.Lookup(name); f != nil {
+				return f
+			}
+		} else if f := flags.ShorthandLookup(shorthand); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// takesValue reports whether f reads the next argument; bool and count
+// flags do not. An unknown flag is assumed not to, so what follows is
+// treated as a positional argument and redacted anyway.
+func takesValue(f *pflag.Flag) bool {
+	return f != nil && f.NoOptDefVal == ""
+}
+
+func isSafeFlag(f *pflag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	if crashSafeFlags[f.Name] {
+		return true
+	}
+	_, ok := f.Annotations[crashSafeAnnotation]
+	return ok
+}
+
+// subcommand returns the child of cmd called name, by name or alias
+func subcommand(cmd *cobra.Command, name string) *cobra.Command {
+	for _, c := range cmd.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
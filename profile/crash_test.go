@@ -0,0 +1,101 @@
+package profile_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+func TestRunProfileRecoversPanics(t *testing.T) {
+	var stderr bytes.Buffer
+	ui.SetOutput(&bytes.Buffer{}, &stderr)
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+	})
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+
+	args := os.Args
+	os.Args = []string{"demo", "sync", "--api-token", "s3cr3t", "--password=hunter2", "--author", "ada"}
+	t.Cleanup(func() { os.Args = args })
+
+	root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo", Version: "1.2.3", IssuesURL: "https://issues.example.com"})
+	root.AddCommand(&cobra.Command{Use: "sync", Run: func(*cobra.Command, []string) { panic("nil map") }})
+	root.SetArgs([]string{"sync"})
+
+	if code := profile.RunProfile(root); code != profile.ExitInternal {
+		t.Fatalf("exit code = %d, want %d", code, profile.ExitInternal)
+	}
+	if got := stderr.String(); !strings.HasPrefix(got, "[✗] demo crashed unexpectedly: nil map\n    hint: Please report it at https://issues.example.com and attach ") {
+		t.Fatalf("unexpected message:\n%s", got)
+	}
+
+	reports, _ := filepath.Glob(filepath.Join(cache, "demo", "crash", "crash-*.log"))
+	if len(reports) != 1 {
+		t.Fatalf("expected one crash report, found %v", reports)
+	}
+	data, err := os.ReadFile(reports[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := string(data)
+	for _, want := range []string{
+		"Version:     1.2.3",
+		"Command:     demo sync --api-token [REDACTED] --password=[REDACTED] --author [REDACTED]",
+		"Panic:       nil map",
+		"Stack:\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report missing %q:\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "s3cr3t") || strings.Contains(report, "hunter2") {
+		t.Error("report leaks secrets")
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+	sync := &cobra.Command{Use: "sync", Aliases: []string{"s"}, Run: func(*cobra.Command, []string) {}}
+	sync.Flags().StringP("password", "p", "", "")
+	sync.Flags().String("token", "", "")
+	sync.Flags().String("author", "", "")
+	sync.Flags().BoolP("use-key", "k", false, "")
+	if err := profile.MarkFlagSafe(sync.Flags(), "author"); err != nil {
+		t.Fatal(err)
+	}
+	root.AddCommand(sync)
+
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"safe global flag", "demo -o json sync", "demo -o json sync"},
+		{"shorthand", "demo sync -p secret", "demo sync -p [REDACTED]"},
+		{"attached shorthand", "demo sync -kpsecret", "demo sync -kp[REDACTED]"},
+		{"value with a dash", "demo sync --token -secret", "demo sync --token [REDACTED]"},
+		{"equals", "demo s --token=secret --output=yaml", "demo s --token=[REDACTED] --output=yaml"},
+		{"positional", "demo sync secret", "demo sync [REDACTED]"},
+		{"after a boolean", "demo sync --use-key secret", "demo sync --use-key [REDACTED]"},
+		{"boolean keeps the command", "demo --verbose sync", "demo --verbose sync"},
+		{"marked safe", "demo sync --author ada", "demo sync --author ada"},
+		{"unknown flag", "demo sync --unknown secret -xsecret", "demo sync --unknown [REDACTED] -x[REDACTED]"},
+		{"after dashes", "demo sync -- sync secret", "demo sync -- [REDACTED] [REDACTED]"},
+	}
+	for _, tt := range tests {
+		got := strings.Join(profile.RedactArgs(root, strings.Fields(tt.args)), " ")
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ikaitla/framework/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	// ProfileEnvVar selects the profile when the binary name does not
+	ProfileEnvVar = "IKAITLA_PROFILE"
+
+	// ProfileFlag selects the profile when the binary name does not
+	ProfileFlag = "profile"
+)
+
+// Dispatcher selects a registered profile from the binary name and runs it
+type Dispatcher struct {
+	profiles []Definition
+	byName   map[string]int
+
+	// Default is used when neither argv[0], --profile nor the environment
+	// select a profile. A single registered profile is always the default.
+	Default string
+}
+
+// NewDispatcher creates a dispatcher holding the given profiles
+func NewDispatcher(defs ...Definition) *Dispatcher {
+	d := &Dispatcher{
+		byName: make(map[string]int),
+	}
+	d.Register(defs...)
+	return d
+}
+
+// Register adds profiles to the dispatcher. It panics when a name or alias
+// is already taken, since that is a wiring bug and not a runtime condition.
+func (d *Dispatcher) Register(defs ...Definition) {
+	for _, def := range defs {
+		if def.Metadata.Name == "" {
+			panic("profile: cannot register a profile without a name")
+		}
+		if def.NewRoot == nil {
+			panic(fmt.Sprintf("profile: %q registered without a root constructor", def.Metadata.Name))
+		}
+
+		idx := len(d.profiles)
+		for _, name := range append([]string{def.Metadata.Name}, def.Metadata.Aliases...) {
+			if prev, ok := d.byName[name]; ok {
+				panic(fmt.Sprintf("profile: %q of %q already registered by %q",
+					name, def.Metadata.Name, d.profiles[prev].Metadata.Name))
+			}
+			d.byName[name] = idx
+		}
+		d.profiles = append(d.profiles, def)
+	}
+}
+
+// Profiles returns the registered profiles sorted by name
+func (d *Dispatcher) Profiles() []Definition {
+	defs := make([]Definition, len(d.profiles))
+	copy(defs, d.profiles)
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Metadata.Name < defs[j].Metadata.Name })
+	return defs
+}
+
+// Lookup finds a profile by name or alias
+func (d *Dispatcher) Lookup(name string) (Definition, bool) {
+	idx, ok := d.byName[name]
+	if !ok {
+		return Definition{}, false
+	}
+	return d.profiles[idx], true
+}
+
+// Resolve picks the profile for a full argument vector (argv[0] included)
+// and returns the arguments to hand to its root command.
+//
+// The binary name wins, so symlinks such as `ik -> ikaitla` select a profile
+// by name or alias; a --profile naming another profile is then an error.
+// Otherwise --profile, then IKAITLA_PROFILE, then Default. Only a --profile
+// among the leading flags counts, so subcommands keep their own --profile.
+func (d *Dispatcher) Resolve(argv []string) (Definition, []string, error) {
+	var args []string
+	if len(argv) > 0 {
+		if def, ok := d.Lookup(binaryName(argv[0])); ok {
+			name, rest, err := extractProfileFlag(argv[1:])
+			if err != nil {
+				return Definition{}, nil, err
+			}
+			if other, ok := d.Lookup(name); name != "" && (!ok || other.Metadata.Name != def.Metadata.Name) {
+				return Definition{}, nil, fmt.Errorf("--%s %s conflicts with the binary name %s, which runs the %s profile",
+					ProfileFlag, name, binaryName(argv[0]), def.Metadata.Name)
+			}
+			return def, rest, nil
+		}
+		args = argv[1:]
+	}
+
+	name, args, err := extractProfileFlag(args)
+	if err != nil {
+		return Definition{}, nil, err
+	}
+	if name == "" {
+		name = os.Getenv(ProfileEnvVar)
+	}
+	if name == "" {
+		name = d.Default
+	}
+	if name == "" && len(d.profiles) == 1 {
+		return d.profiles[0], args, nil
+	}
+
+	if name == "" {
+		return Definition{}, nil, fmt.Errorf("no profile selected: use --%s or %s (available: %s)",
+			ProfileFlag, ProfileEnvVar, strings.Join(d.names(), ", "))
+	}
+	def, ok := d.Lookup(name)
+	if !ok {
+		return Definition{}, nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(d.names(), ", "))
+	}
+	return def, args, nil
+}
+
+// Execute resolves the profile from os.Args and runs it
+func (d *Dispatcher) Execute() {
+	def, args, err := d.Resolve(os.Args)
+	if err != nil {
+		applyOutputFlag(os.Args[1:])
+		f := failure(nil, WrapError(CategoryUsage, err))
+		ui.Fail(f)
+		os.Exit(f.Code)
+	}
+
+	root := def.NewRoot()
+	root.SetArgs(args)
+	ExecuteProfile(root)
+}
+
+// InstallSymlinks creates one symlink per profile name and alias in dir,
+// all pointing at target. Existing symlinks are replaced; regular files are
+// left alone and reported as an error.
+func (d *Dispatcher) InstallSymlinks(target, dir string) ([]string, error) {
+	var created []string
+	for _, def := range d.Profiles() {
+		for _, name := range append([]string{def.Metadata.Name}, def.Metadata.Aliases...) {
+			link := filepath.Join(dir, name)
+			if link == target {
+				continue
+			}
+			if fi, err := os.Lstat(link); err == nil {
+				if fi.Mode()&os.ModeSymlink == 0 {
+					return created, fmt.Errorf("%s exists and is not a symlink", link)
+				}
+				if err := os.Remove(link); err != nil {
+					return created, err
+				}
+			}
+			if err := os.Symlink(target, link); err != nil {
+				return created, err
+			}
+			created = append(created, link)
+		}
+	}
+	return created, nil
+}
+
+func (d *Dispatcher) names() []string {
+	names := make([]string, 0, len(d.profiles))
+	for _, def := range d.Profiles() {
+		names = append(names, def.Metadata.Name)
+	}
+	return names
+}
+
+// binaryName strips the directory and, on Windows, the .exe suffix
+func binaryName(arg0 string) string {
+	name := filepath.Base(arg0)
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".exe") {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return name
+}
+
+// dispatchFlags are the global flags every root shares, known before a
+// profile is picked, so extractProfileFlag can skip their values
+var dispatchFlags = func() *pflag.FlagSet {
+	cmd := &cobra.Command{}
+	addGlobalFlags(cmd)
+	return cmd.PersistentFlags()
+}()
+
+// extractProfileFlag removes --profile <name> / --profile=<name> from the
+// flags before the first argument that is not a flag, usually the command
+// name, or the "--" terminator. The values of the global flags, as in
+// "-o json --profile db", are not taken for the command name.
+func extractProfileFlag(args []string) (string, []string, error) {
+	var name string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--" || !strings.HasPrefix(arg, "-"):
+			return name, append(rest, args[i:]...), nil
+		case arg == "--"+ProfileFlag:
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("flag needs an argument: --%s", ProfileFlag)
+			}
+			name = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--"+ProfileFlag+"="):
+			name = strings.TrimPrefix(arg, "--"+ProfileFlag+"=")
+		default:
+			rest = append(rest, arg)
+			if flagValueFollows(arg) && i+1 < len(args) {
+				i++
+				rest = append(rest, args[i])
+			}
+		}
+	}
+	return name, rest, nil
+}
+
+// flagValueFollows reports whether arg is a global flag that reads the next
+// argument as its value: --output, or a run of shorthands such as -vo ending
+// with one that takes a value
+func flagValueFollows(arg string) bool {
+	if long, ok := strings.CutPrefix(arg, "--"); ok {
+		return !strings.Contains(long, "=") && takesValue(dispatchFlags.Lookup(long))
+	}
+	for j := 1; j < len(arg); j++ {
+		f := dispatchFlags.ShorthandLookup(arg[j : j+1])
+		if f == nil {
+			return false
+		}
+		if takesValue(f) {
+			return j == len(arg)-1
+		}
+	}
+	return false
+}
+
+// Global profile dispatcher
+var defaultDispatcher = NewDispatcher()
+
+// RegisterProfiles registers profiles with the global dispatcher, typically
+// with the generated Profiles slice
+func RegisterProfiles(defs ...Definition) {
+	defaultDispatcher.Register(defs...)
+}
+
+// RegisteredProfiles returns every profile known to the global dispatcher
+func RegisteredProfiles() []Definition {
+	return defaultDispatcher.Profiles()
+}
+
+// Dispatch runs the profile selected by os.Args on the global dispatcher
+func Dispatch() {
+	defaultDispatcher.Execute()
+}
@@ -0,0 +1,132 @@
+package profile_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/profile"
+	"github.com/spf13/cobra"
+)
+
+func definition(name string, aliases ...string) profile.Definition {
+	meta := profile.ProfileMetadata{Name: name, Aliases: aliases}
+	return profile.Definition{
+		Metadata: meta,
+		NewRoot:  func() *cobra.Command { return profile.NewRootCommand(meta) },
+	}
+}
+
+func TestDispatcherResolve(t *testing.T) {
+	d := profile.NewDispatcher(definition("ikaitla", "ik"), definition("deploy"))
+
+	tests := []struct {
+		name     string
+		argv     []string
+		env      string
+		wantName string
+		wantArgs string
+	}{
+		{"binary name", []string{"/usr/local/bin/deploy", "status"}, "", "deploy", "status"},
+		{"alias symlink", []string{"ik", "init", "x"}, "", "ikaitla", "init x"},
+		{"windows exe", []string{"bin/deploy.exe"}, "", "deploy", ""},
+		{"profile flag", []string{"app", "--profile", "deploy", "status"}, "", "deploy", "status"},
+		{"profile flag equals", []string{"app", "--profile=ik", "status"}, "deploy", "ikaitla", "status"},
+		{"flag after terminator", []string{"app", "--", "--profile=ik"}, "deploy", "deploy", "-- --profile=ik"},
+		{"flag after command", []string{"app", "status", "--profile", "ik"}, "deploy", "deploy", "status --profile ik"},
+		{"leading flags", []string{"app", "-v", "--profile", "ik", "status"}, "", "ikaitla", "-v status"},
+		{"after a flag value", []string{"app", "-o", "json", "--profile", "ik", "status"}, "", "ikaitla", "-o json status"},
+		{"after a long flag value", []string{"app", "--context", "prod", "--profile=ik", "status"}, "", "ikaitla", "--context prod status"},
+		{"after a shorthand run", []string{"app", "-vo", "yaml", "--profile", "ik", "status"}, "", "ikaitla", "-vo yaml status"},
+		{"after an inline value", []string{"app", "-ojson", "--output=yaml", "--profile", "ik", "status"}, "", "ikaitla", "-ojson --output=yaml status"},
+		{"binary keeps subcommand flag", []string{"deploy", "run", "--profile=prod"}, "", "deploy", "run --profile=prod"},
+		{"binary with same profile", []string{"ik", "--profile", "ikaitla", "init"}, "", "ikaitla", "init"},
+		{"environment", []string{"app", "status"}, "ikaitla", "ikaitla", "status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(profile.ProfileEnvVar, tt.env)
+			def, args, err := d.Resolve(tt.argv)
+			if err != nil {
+				t.Fatalf("Resolve: %v", err)
+			}
+			if def.Metadata.Name != tt.wantName {
+				t.Errorf("profile = %q, want %q", def.Metadata.Name, tt.wantName)
+			}
+			if got := strings.Join(args, " "); got != tt.wantArgs {
+				t.Errorf("args = %q, want %q", got, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestDispatcherResolve_Errors(t *testing.T) {
+	t.Setenv(profile.ProfileEnvVar, "")
+	d := profile.NewDispatcher(definition("ikaitla"), definition("deploy"))
+
+	if _, _, err := d.Resolve([]string{"app"}); err == nil {
+		t.Error("expected error when no profile is selected")
+	}
+	if _, _, err := d.Resolve([]string{"app", "--profile", "nope"}); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	if _, _, err := d.Resolve([]string{"deploy", "--profile", "ikaitla", "status"}); err == nil {
+		t.Error("expected error for a --profile conflicting with the binary name")
+	}
+
+	single := profile.NewDispatcher(definition("solo"))
+	if def, _, err := single.Resolve([]string{"app"}); err != nil || def.Metadata.Name != "solo" {
+		t.Errorf("single profile should be the default, got %q, %v", def.Metadata.Name, err)
+	}
+}
+
+func TestDispatcherRegister_DuplicateAlias(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic on duplicate alias")
+		}
+	}()
+	profile.NewDispatcher(definition("ikaitla", "ik"), definition("ik"))
+}
+
+func TestDispatcherInstallSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "ikaitla-bin")
+	if err := os.WriteFile(target, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/stale", filepath.Join(dir, "ik")); err != nil {
+		t.Fatal(err)
+	}
+	d := profile.NewDispatcher(definition("ikaitla", "ik"), definition("deploy"))
+
+	created, err := d.InstallSymlinks(target, dir)
+	if err != nil {
+		t.Fatalf("InstallSymlinks: %v", err)
+	}
+	if len(created) != 3 {
+		t.Errorf("created = %v, want deploy, ikaitla and ik", created)
+	}
+	for _, name := range []string{"deploy", "ikaitla", "ik"} {
+		if got, err := os.Readlink(filepath.Join(dir, name)); err != nil || got != target {
+			t.Errorf("%s -> %q, %v; want %s", name, got, err, target)
+		}
+	}
+
+	if err := os.Remove(filepath.Join(dir, "deploy")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "deploy"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.InstallSymlinks(target, dir); err == nil {
+		t.Error("expected an error when a regular file is in the way")
+	}
+}
@@ -0,0 +1,173 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ikaitla/framework/ui"
+	"github.com/spf13/cobra"
+)
+
+// Category classifies an Error for its exit code and rendering
+type Category string
+
+const (
+	CategoryUsage    Category = "usage"
+	CategoryConfig   Category = "config"
+	CategoryNetwork  Category = "network"
+	CategoryInternal Category = "internal"
+
+	// CategoryCheck is a failed health check, as reported by doctor
+	CategoryCheck Category = "check"
+)
+
+// Exit codes returned by RunProfile, following sysexits(3) where it has one
+const (
+	ExitOK       = 0
+	ExitError    = 1
+	ExitUsage    = 2
+	ExitNetwork  = 69
+	ExitInternal = 70
+	ExitConfig   = 78
+)
+
+var categoryExitCodes = map[Category]int{
+	CategoryUsage:    ExitUsage,
+	CategoryConfig:   ExitConfig,
+	CategoryNetwork:  ExitNetwork,
+	CategoryInternal: ExitInternal,
+}
+
+// Error is an error meant for the user: it carries an exit code, a
+// category, a hint on how to fix it and an optional docs link, which
+// defaults to the profile's DocsURL. Return it (or wrap it) from RunE.
+type Error struct {
+	Err      error
+	Category Category
+	Code     int
+	Hint     string
+	DocsURL  string
+
+	// Silent errors were already reported by the command, so RunProfile
+	// only exits with their code
+	Silent bool
+}
+
+// NewError creates an Error in the given category
+func NewError(category Category, format string, args ...any) *Error {
+	return &Error{Err: fmt.Errorf(format, args...), Category: category}
+}
+
+// WrapError attaches a category to err; it returns nil for a nil err
+func WrapError(category Category, err error) *Error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Err: err, Category: category}
+}
+
+// UsageError reports invalid arguments or flags
+func UsageError(format string, args ...any) *Error {
+	return NewError(CategoryUsage, format, args...)
+}
+
+// ConfigError reports a missing or invalid configuration
+func ConfigError(format string, args ...any) *Error {
+	return NewError(CategoryConfig, format, args...)
+}
+
+// NetworkError reports an unreachable or failing remote service
+func NetworkError(format string, args ...any) *Error {
+	return NewError(CategoryNetwork, format, args...)
+}
+
+// InternalError reports a bug or an unexpected state
+func InternalError(format string, args ...any) *Error {
+	return NewError(CategoryInternal, format, args...)
+}
+
+// WithHint sets the suggestion shown below the message
+func (e *Error) WithHint(format string, args ...any) *Error {
+	e.Hint = fmt.Sprintf(format, args...)
+	return e
+}
+
+// WithDocs sets the documentation link shown below the message
+func (e *Error) WithDocs(url string) *Error {
+	e.DocsURL = url
+	return e
+}
+
+// Silence marks the error as already reported, e.g. by a command whose
+// structured output describes the failure
+func (e *Error) Silence() *Error {
+	e.Silent = true
+	return e
+}
+
+// WithCode overrides the category's exit code
+func (e *Error) WithCode(code int) *Error {
+	e.Code = code
+	return e
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return string(e.Category) + " error"
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// ExitCode returns Code, or the category's default
+func (e *Error) ExitCode() int {
+	if e.Code != 0 {
+		return e.Code
+	}
+	if code, ok := categoryExitCodes[e.Category]; ok {
+		return code
+	}
+	return ExitError
+}
+
+// failure describes err for ui.Fail. cmd is the command that failed and
+// may be nil; it supplies the docs default and the usage hint.
+func failure(cmd *cobra.Command, err error) ui.Failure {
+	f := ui.Failure{Message: err.Error(), Code: ExitError}
+
+	var e *Error
+	if !errors.As(err, &e) && isCobraUsageError(err) {
+		e = &Error{Err: err, Category: CategoryUsage}
+	}
+	if e == nil {
+		return f
+	}
+
+	f.Category = string(e.Category)
+	f.Code = e.ExitCode()
+	f.Hint = e.Hint
+	f.DocsURL = e.DocsURL
+	if cmd != nil {
+		if f.Hint == "" && e.Category == CategoryUsage {
+			f.Hint = fmt.Sprintf("Run '%s --help' for usage.", cmd.CommandPath())
+		}
+		if meta, ok := MetadataOf(cmd); ok && f.DocsURL == "" {
+			f.DocsURL = meta.DocsURL
+		}
+	}
+	return f
+}
+
+// isCobraUsageError recognizes the argument errors cobra creates itself
+func isCobraUsageError(err error) bool {
+	msg := err.Error()
+	for _, prefix := range []string{"unknown command", "unknown flag", "unknown shorthand flag",
+		"required flag", "accepts ", "requires at least", "requires at most", "invalid argument"} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
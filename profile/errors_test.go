@@ -0,0 +1,122 @@
+package profile_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+func TestRunProfileRendersErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	ui.SetOutput(&stdout, &stderr)
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+		ui.SetFormat(output.Text)
+	})
+
+	newRoot := func(err error) *cobra.Command {
+		root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo", DocsURL: "https://docs.example.com"})
+		root.AddCommand(&cobra.Command{Use: "sync", RunE: func(*cobra.Command, []string) error { return err }})
+		return root
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		err  error
+		code int
+		want string
+	}{
+		{"plain", []string{"sync"}, errors.New("boom"), profile.ExitError, "[✗] boom\n"},
+		{"config", []string{"sync"},
+			fmt.Errorf("load: %w", profile.ConfigError("missing token").WithHint("Run 'demo login'.")),
+			profile.ExitConfig,
+			"[✗] load: missing token\n    hint: Run 'demo login'.\n    docs: https://docs.example.com\n"},
+		{"unknown flag", []string{"sync", "--nope"}, nil, profile.ExitUsage,
+			"[✗] unknown flag: --nope\n    hint: Run 'demo sync --help' for usage.\n    docs: https://docs.example.com\n"},
+		{"custom code", []string{"sync"}, profile.NetworkError("timeout").WithCode(3).WithDocs("https://status"), 3,
+			"[✗] timeout\n    docs: https://status\n"},
+		{"silent", []string{"sync"}, fmt.Errorf("doctor: %w", profile.NewError(profile.CategoryCheck, "2 checks failed").Silence()),
+			profile.ExitError, ""},
+	}
+	for _, tt := range tests {
+		stderr.Reset()
+		root := newRoot(tt.err)
+		root.SetArgs(tt.args)
+		if code := profile.RunProfile(root); code != tt.code {
+			t.Errorf("%s: exit code = %d, want %d", tt.name, code, tt.code)
+		}
+		if got := stderr.String(); got != tt.want {
+			t.Errorf("%s: got:\n%s\nwant:\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	stderr.Reset()
+	root := newRoot(profile.UsageError("bad name %q", "x"))
+	root.SetArgs([]string{"sync", "-o", "json"})
+	if code := profile.RunProfile(root); code != profile.ExitUsage {
+		t.Fatalf("json: exit code = %d", code)
+	}
+	want := `{
+  "error": {
+    "message": "bad name \"x\"",
+    "category": "usage",
+    "code": 2,
+    "hint": "Run 'demo sync --help' for usage.",
+    "docs": "https://docs.example.com"
+  }
+}
+`
+	if got := stderr.String(); got != want {
+		t.Fatalf("json: got:\n%s\nwant:\n%s", got, want)
+	}
+	if strings.TrimSpace(stdout.String()) != "" {
+		t.Fatalf("unexpected stdout: %q", stdout.String())
+	}
+}
+
+func TestRunProfileRendersRejectedArgsInOutputFormat(t *testing.T) {
+	var stderr bytes.Buffer
+	ui.SetOutput(io.Discard, &stderr)
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+		ui.SetFormat(output.Text)
+	})
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-o", "json", "nope"}, `"message": "unknown command \"nope\" for \"demo\""`},
+		{[]string{"-o", "json", "sync", "--bogus"}, `"message": "unknown flag: --bogus"`},
+		{[]string{"sync", "--bogus", "--output=yaml"}, "error:\n  message: 'unknown flag: --bogus'\n"},
+		{[]string{"-o", "template={{.name}}", "nope"}, `"category": "usage"`},
+		{[]string{"-o", "csv", "nope"}, `[✗] unknown command "nope" for "demo"`},
+	} {
+		stderr.Reset()
+		ui.SetFormat(output.Text)
+		root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+		root.AddCommand(&cobra.Command{Use: "sync", Run: func(*cobra.Command, []string) {}})
+		root.SetArgs(tt.args)
+		if code := profile.RunProfileArgs(root, tt.args); code != profile.ExitUsage {
+			t.Errorf("%v: exit code = %d, want %d", tt.args, code, profile.ExitUsage)
+		}
+		if got := stderr.String(); !strings.Contains(got, tt.want) {
+			t.Errorf("%v: stderr = %q, want it to contain %q", tt.args, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package profile
+
+// WatchSignals exposes watchSignals to the external tests
+var WatchSignals = watchSignals
+
+// RedactArgs exposes redactArgs to the external tests
+var RedactArgs = redactArgs
+
+// RunProfileArgs exposes runProfile to the external tests
+var RunProfileArgs = runProfile
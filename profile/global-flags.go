@@ -0,0 +1,146 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+// Global flag names registered by NewRootCommand
+const (
+	OutputFlag   = "output"
+	VerboseFlag  = "verbose"
+	LogLevelFlag = "log-level"
+	NoColorFlag  = "no-color"
+	ColumnsFlag  = "columns"
+	QueryFlag    = "query"
+	FieldsFlag   = "fields"
+	ContextFlag  = "context"
+)
+
+// addGlobalFlags registers the persistent flags every profile shares
+func addGlobalFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP(OutputFlag, "o", string(output.Text),
+		fmt.Sprintf("Output format (%s)", strings.Join(output.FormatNames(), "|")))
+	cmd.PersistentFlags().CountP(VerboseFlag, "v", "Verbose output (-v for debug logs, -vv for trace)")
+	cmd.PersistentFlags().String(LogLevelFlag, "",
+		fmt.Sprintf("Log level (%s), overrides -v", strings.Join(output.LevelNames(), "|")))
+	cmd.PersistentFlags().Bool(NoColorFlag, false, "Disable colored output")
+	cmd.PersistentFlags().StringSlice(ColumnsFlag, nil, "Columns of struct output to keep, in order, before --query and --fields apply (e.g. name,size)")
+	cmd.PersistentFlags().String(QueryFlag, "", "Query to apply to the output (e.g. \"items[?state=='failed'].name\")")
+	cmd.PersistentFlags().StringSlice(FieldsFlag, nil, "Fields to keep in the output, in order (e.g. name,size)")
+	cmd.PersistentFlags().String(ProfileFlag, "",
+		fmt.Sprintf("Profile to run when the binary name does not select one (or %s); must come first", ProfileEnvVar))
+	cmd.PersistentFlags().String(ContextFlag, "", "Context of the profile configuration to use (e.g. staging)")
+}
+
+// applyOutputFlag sets the ui format from the --output in args, for errors
+// cobra reports before ApplyGlobalFlags runs. The other flags are parsed
+// only so their values are not mistaken for one.
+func applyOutputFlag(args []string) {
+	cmd := &cobra.Command{}
+	addGlobalFlags(cmd)
+	flags := cmd.PersistentFlags()
+	flags.ParseErrorsAllowlist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	_ = flags.Parse(args)
+	if f := flags.Lookup(OutputFlag); f.Changed {
+		_ = ui.SetFormatSpec(f.Value.String())
+	}
+}
+
+// ApplyGlobalFlags binds the profile configuration into the flags of cmd,
+// then configures the shared ui output from the global flags.
+// NewRootCommand installs it as PersistentPreRunE; a subcommand that defines
+// its own PersistentPreRun(E) shadows it and should call it first.
+// Commands marked with AllowBrokenConfig run past configuration errors with
+// the faulty settings at their defaults.
+func ApplyGlobalFlags(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+
+	var skipped []error
+	skip := func(e *Error) bool {
+		if e.Category != CategoryConfig || !allowsBrokenConfig(cmd) {
+			return false
+		}
+		skipped = append(skipped, e)
+		return true
+	}
+	defer func() {
+		if len(skipped) > 0 {
+			skippedConfigs.Store(cmd.Root(), errors.Join(skipped...))
+		} else {
+			skippedConfigs.Delete(cmd.Root())
+		}
+	}()
+
+	// The dispatcher consumes --profile among the global flags before the
+	// command name; one that reaches cobra, e.g. after a flag of the profile
+	// itself, came too late to select anything
+	if f := flags.Lookup(ProfileFlag); f != nil && f.Changed && f == cmd.Root().PersistentFlags().Lookup(ProfileFlag) {
+		return UsageError("--%s was not read before the profile started", ProfileFlag).
+			WithHint("Give --%s as the first argument, or set %s.", ProfileFlag, ProfileEnvVar)
+	}
+
+	if err := bindConfig(cmd, skip); err != nil {
+		return err
+	}
+
+	if f := flags.Lookup(OutputFlag); f != nil {
+		if err := ui.SetFormatSpec(f.Value.String()); err != nil {
+			if e := flagError(cmd, OutputFlag, err); !skip(e) {
+				return e
+			}
+			_ = ui.SetFormatSpec(f.DefValue)
+		}
+	}
+
+	// --no-color=false, or false from the environment or the file, turns
+	// colors back to auto rather than leaving the mode alone
+	if f := flags.Lookup(NoColorFlag); f != nil && flagSet(cmd, f) {
+		mode := term.ColorAuto
+		if noColor, _ := flags.GetBool(NoColorFlag); noColor {
+			mode = term.ColorNever
+		}
+		ui.SetColorMode(mode)
+	}
+
+	verbosity, _ := flags.GetCount(VerboseFlag)
+	ui.SetVerbosity(verbosity)
+	level := output.VerbosityLevel(verbosity)
+	if name, err := flags.GetString(LogLevelFlag); err == nil && name != "" {
+		if parsed, err := output.ParseLevel(name); err == nil {
+			level = parsed
+		} else if e := flagError(cmd, LogLevelFlag, err); !skip(e) {
+			return e
+		}
+	}
+	ui.SetLogLevel(level)
+
+	if columns, err := flags.GetStringSlice(ColumnsFlag); err == nil {
+		ui.SetColumns(columns)
+	}
+
+	if query, err := flags.GetString(QueryFlag); err == nil {
+		if err := ui.SetQuery(query); err != nil {
+			if e := flagError(cmd, QueryFlag, err); !skip(e) {
+				return e
+			}
+			_ = ui.SetQuery("")
+		}
+	}
+
+	if fields, err := flags.GetStringSlice(FieldsFlag); err == nil {
+		ui.SetFields(fields)
+	}
+
+	warnDangerousContext(cmd)
+
+	return nil
+}
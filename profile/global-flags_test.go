@@ -0,0 +1,268 @@
+package profile_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+// runRoot executes a fresh root command, since flag values persist
+// between executions of the same command
+func runRoot(args ...string) error {
+	root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+	root.AddCommand(&cobra.Command{Use: "noop", Run: func(*cobra.Command, []string) {}})
+	root.SetOut(io.Discard)
+	root.SetErr(io.Discard)
+	root.SetArgs(args)
+	return root.Execute()
+}
+
+func TestRootCommandAppliesGlobalFlags(t *testing.T) {
+	t.Cleanup(func() {
+		ui.SetFormat(output.Text)
+		ui.SetVerbosity(0)
+		ui.SetLogLevel(output.LevelInfo)
+	})
+
+	if err := runRoot("noop", "-o", "json", "--verbose", "--no-color"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Format() != output.JSON {
+		t.Errorf("format = %q, want json", ui.Format())
+	}
+	if ui.Verbosity() != 1 {
+		t.Errorf("verbosity = %d, want 1", ui.Verbosity())
+	}
+	if ui.ColorsEnabled() {
+		t.Error("colors should be disabled by --no-color")
+	}
+
+	// false, given or bound, turns colors back to auto; the last run leaves
+	// the mode as the other tests expect it
+	t.Setenv("DEMO_NO_COLOR", "true")
+	for _, tc := range []struct {
+		args []string
+		want term.ColorMode
+	}{
+		{[]string{"noop", "--no-color=false"}, term.ColorAuto},
+		{[]string{"noop"}, term.ColorNever},
+		{[]string{"noop", "--no-color=false"}, term.ColorAuto},
+	} {
+		if err := runRoot(tc.args...); err != nil {
+			t.Fatalf("Execute %v: %v", tc.args, err)
+		}
+		if got := ui.ColorMode(); got != tc.want {
+			t.Errorf("%v with DEMO_NO_COLOR=true: color mode = %v, want %v", tc.args, got, tc.want)
+		}
+	}
+	t.Setenv("DEMO_NO_COLOR", "false")
+	ui.SetColorMode(term.ColorNever)
+	if err := runRoot("noop"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got := ui.ColorMode(); got != term.ColorAuto {
+		t.Errorf("DEMO_NO_COLOR=false: color mode = %v, want auto", got)
+	}
+
+	if err := runRoot("noop", "-vv"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Verbosity() != 2 || ui.LogLevel() != output.LevelTrace {
+		t.Errorf("-vv: verbosity = %d, level = %v", ui.Verbosity(), ui.LogLevel())
+	}
+
+	if err := runRoot("noop", "-v", "--log-level", "error"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.LogLevel() != output.LevelError {
+		t.Errorf("--log-level error: level = %v", ui.LogLevel())
+	}
+
+	if err := runRoot("noop", "-o", "xml"); err == nil {
+		t.Error("expected error for unknown output format")
+	}
+
+	if err := runRoot("noop", "--profile", "other"); err == nil {
+		t.Error("expected error for --profile after the command")
+	}
+}
+
+func TestRootCommandReadsConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "demo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "demo", "config.yaml"), []byte("output: yaml\nlog-level: debug\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("DEMO_LOG_LEVEL", "warn")
+	t.Cleanup(func() {
+		ui.SetFormat(output.Text)
+		ui.SetLogLevel(output.LevelInfo)
+	})
+
+	if err := runRoot("noop"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Format() != output.YAML || ui.LogLevel() != output.LevelWarn {
+		t.Errorf("format = %q, level = %v; want yaml from the file, warn from the env", ui.Format(), ui.LogLevel())
+	}
+
+	if err := runRoot("noop", "-o", "json"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Format() != output.JSON {
+		t.Errorf("format = %q, want json from the flag", ui.Format())
+	}
+
+	t.Setenv("DEMO_OUTPUT", "xml")
+	var e *profile.Error
+	if err := runRoot("noop"); !errors.As(err, &e) || e.Category != profile.CategoryConfig {
+		t.Errorf("invalid env value: err = %v, want a config error", err)
+	}
+}
+
+func TestRootCommandUsesContext(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "demo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := "context: dev\ncontexts:\n  dev:\n    output: yaml\n  prod:\n    output: json\n    dangerous: true\n"
+	if err := os.WriteFile(filepath.Join(dir, "demo", "config.yaml"), []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stderr bytes.Buffer
+	ui.SetOutput(os.Stdout, &stderr)
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+		ui.SetFormat(output.Text)
+	})
+
+	if err := runRoot("noop"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Format() != output.YAML || stderr.Len() != 0 {
+		t.Errorf("dev: format = %q, stderr = %q", ui.Format(), stderr.String())
+	}
+
+	if err := runRoot("noop", "--context", "prod"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if ui.Format() != output.JSON {
+		t.Errorf("prod: format = %q, want json", ui.Format())
+	}
+	if want := "[!] Context prod is marked dangerous\n"; stderr.String() != want {
+		t.Errorf("prod: stderr = %q, want %q", stderr.String(), want)
+	}
+
+	var e *profile.Error
+	if err := runRoot("noop", "--context", "staging"); !errors.As(err, &e) || e.Category != profile.CategoryUsage {
+		t.Errorf("unknown context: err = %v, want a usage error", err)
+	}
+}
+
+func TestAllowBrokenConfig(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "demo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "demo", "config.yaml")
+	t.Cleanup(func() {
+		ui.SetFormat(output.Text)
+		ui.SetLogLevel(output.LevelInfo)
+	})
+
+	run := func(args ...string) (skipped, err error) {
+		t.Helper()
+		root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+		root.AddCommand(&cobra.Command{Use: "noop", Run: func(*cobra.Command, []string) {}})
+		repair := &cobra.Command{Use: "repair", Run: func(cmd *cobra.Command, _ []string) {
+			skipped = profile.ConfigErrorOf(cmd)
+		}}
+		profile.AllowBrokenConfig(repair)
+		root.AddCommand(repair)
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		root.SetArgs(args)
+		err = root.Execute()
+		return skipped, err
+	}
+
+	for name, content := range map[string]string{
+		"invalid values": "output: xml\nlog-level: loud\nquery: \"[\"\n",
+		"parse error":    "output: [json\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		var e *profile.Error
+		if _, err := run("noop"); !errors.As(err, &e) || e.Category != profile.CategoryConfig {
+			t.Errorf("%s: noop err = %v, want a config error", name, err)
+		}
+		skipped, err := run("repair")
+		if err != nil {
+			t.Errorf("%s: repair should run, got %v", name, err)
+		}
+		if skipped == nil {
+			t.Errorf("%s: ConfigErrorOf should report the broken settings", name)
+		}
+		if ui.Format() != output.Text || ui.LogLevel() != output.LevelInfo {
+			t.Errorf("%s: format = %q, level = %v; want the defaults", name, ui.Format(), ui.LogLevel())
+		}
+	}
+
+	var e *profile.Error
+	if _, err := run("repair", "-o", "xml"); !errors.As(err, &e) || e.Category != profile.CategoryUsage {
+		t.Errorf("an invalid flag should still fail: err = %v", err)
+	}
+}
+
+func TestValidateSetting(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "demo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "demo", "config.yaml"), []byte("contexts:\n  dev:\n    output: yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+
+	for _, tc := range []struct {
+		key, value string
+		ok         bool
+	}{
+		{"output", "json", true},
+		{"output", "template={{.name}}", true},
+		{"output", "xml", false},
+		{"output", "template={{", false},
+		{"contexts.dev.output", "xml", false},
+		{"log-level", "debug", true},
+		{"log-level", "loud", false},
+		{"query", "items[0].name", true},
+		{"query", "[", false},
+		{"context", "dev", true},
+		{"context", "staging", false},
+		{"token", "anything", true},
+	} {
+		err := profile.ValidateSetting(root, tc.key, tc.value)
+		if (err == nil) != tc.ok {
+			t.Errorf("ValidateSetting(%q, %q) = %v, want ok=%v", tc.key, tc.value, err, tc.ok)
+		}
+	}
+}
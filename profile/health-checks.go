@@ -0,0 +1,187 @@
+package profile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CheckStatus is the outcome of a health check
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// DefaultCheckTimeout bounds each check run by doctor
+const DefaultCheckTimeout = 10 * time.Second
+
+// CheckResult is what a check returns: a status, a short message and, for
+// warnings and failures, how to fix the problem
+type CheckResult struct {
+	Status      CheckStatus
+	Message     string
+	Remediation string
+}
+
+// CheckPassed reports a successful check
+func CheckPassed(format string, args ...any) CheckResult {
+	return CheckResult{Status: CheckOK, Message: fmt.Sprintf(format, args...)}
+}
+
+// CheckWarning reports a problem that does not stop the CLI from working
+func CheckWarning(remediation, format string, args ...any) CheckResult {
+	return CheckResult{Status: CheckWarn, Message: fmt.Sprintf(format, args...), Remediation: remediation}
+}
+
+// CheckFailed reports a problem that breaks the CLI
+func CheckFailed(remediation, format string, args ...any) CheckResult {
+	return CheckResult{Status: CheckFail, Message: fmt.Sprintf(format, args...), Remediation: remediation}
+}
+
+// Check is a named health check run by the doctor command. Run should
+// return when ctx is done; a check that does not is reported as timed out.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) CheckResult
+}
+
+// CheckReport is the result of one check as doctor renders it
+type CheckReport struct {
+	Name        string        `json:"name"`
+	Status      CheckStatus   `json:"status"`
+	Message     string        `json:"message"`
+	Remediation string        `json:"remediation,omitempty"`
+	Duration    time.Duration `json:"-"`
+}
+
+// MarshalJSON writes the duration in milliseconds
+func (r CheckReport) MarshalJSON() ([]byte, error) {
+	type report CheckReport
+	return json.Marshal(struct {
+		report
+		Duration int64 `json:"duration_ms"`
+	}{report(r), r.Duration.Milliseconds()})
+}
+
+// CheckRegistry holds the health checks registered by profiles
+type CheckRegistry struct {
+	mu     sync.Mutex
+	checks []Check
+	names  map[string]bool
+}
+
+// NewCheckRegistry creates an empty check registry
+func NewCheckRegistry() *CheckRegistry {
+	return &CheckRegistry{names: make(map[string]bool)}
+}
+
+// Register adds checks to the registry. It panics on a missing run function
+// or a name already taken, since that is a wiring bug.
+func (r *CheckRegistry) Register(checks ...Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range checks {
+		if c.Name == "" || c.Run == nil {
+			panic("profile: a check needs a name and a run function")
+		}
+		if r.names[c.Name] {
+			panic(fmt.Sprintf("profile: check %q already registered", c.Name))
+		}
+		r.names[c.Name] = true
+		r.checks = append(r.checks, c)
+	}
+}
+
+// Checks returns the registered checks in registration order
+func (r *CheckRegistry) Checks() []Check {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	checks := make([]Check, len(r.checks))
+	copy(checks, r.checks)
+	return checks
+}
+
+// RunChecks runs checks concurrently, each bounded by timeout, and returns
+// their reports in the order of checks. A check that panics or outlives its
+// timeout fails. progress, when not nil, is called as each check finishes.
+func RunChecks(ctx context.Context, checks []Check, timeout time.Duration, progress func(CheckReport)) []CheckReport {
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	reports := make([]CheckReport, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report := runCheck(ctx, c, timeout)
+			reports[i] = report
+			if progress != nil {
+				mu.Lock()
+				progress(report)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	return reports
+}
+
+func runCheck(ctx context.Context, c Check, timeout time.Duration) CheckReport {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan CheckResult, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- CheckFailed("This is a bug in the check; please report it.", "check panicked: %v", r)
+			}
+		}()
+		done <- c.Run(ctx)
+	}()
+
+	var result CheckResult
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result = CheckFailed("Retry with a longer --timeout.", "timed out after %s", timeout)
+		} else {
+			result = CheckFailed("", "cancelled")
+		}
+	}
+	if result.Status == "" {
+		result.Status = CheckOK
+	}
+
+	return CheckReport{
+		Name:        c.Name,
+		Status:      result.Status,
+		Message:     result.Message,
+		Remediation: result.Remediation,
+		Duration:    time.Since(start),
+	}
+}
+
+// Global health check registry
+var checkRegistry = NewCheckRegistry()
+
+// RegisterCheck registers health checks run by the doctor command
+func RegisterCheck(checks ...Check) {
+	checkRegistry.Register(checks...)
+}
+
+// RegisteredChecks returns every check of the global registry
+func RegisteredChecks() []Check {
+	return checkRegistry.Checks()
+}
@@ -0,0 +1,80 @@
+package profile_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ikaitla/framework/profile"
+)
+
+func TestRunChecks(t *testing.T) {
+	release := make(chan struct{})
+	checks := []profile.Check{
+		{Name: "slow", Run: func(ctx context.Context) profile.CheckResult {
+			<-release
+			return profile.CheckPassed("done")
+		}},
+		{Name: "warn", Run: func(context.Context) profile.CheckResult {
+			close(release) // runs while "slow" waits, so checks are concurrent
+			return profile.CheckWarning("Renew it.", "expires in %d days", 2)
+		}},
+		{Name: "hang", Run: func(ctx context.Context) profile.CheckResult {
+			select {}
+		}},
+		{Name: "panic", Run: func(context.Context) profile.CheckResult {
+			panic("boom")
+		}},
+	}
+
+	var finished int
+	reports := profile.RunChecks(context.Background(), checks, 100*time.Millisecond, func(profile.CheckReport) { finished++ })
+	if finished != len(checks) {
+		t.Errorf("progress called %d times, want %d", finished, len(checks))
+	}
+
+	want := []struct {
+		name    string
+		status  profile.CheckStatus
+		message string
+	}{
+		{"slow", profile.CheckOK, "done"},
+		{"warn", profile.CheckWarn, "expires in 2 days"},
+		{"hang", profile.CheckFail, "timed out after 100ms"},
+		{"panic", profile.CheckFail, "check panicked: boom"},
+	}
+	for i, w := range want {
+		r := reports[i]
+		if r.Name != w.name || r.Status != w.status || r.Message != w.message {
+			t.Errorf("report %d = %+v, want %s %s %q", i, r, w.name, w.status, w.message)
+		}
+	}
+	if reports[1].Remediation != "Renew it." {
+		t.Errorf("remediation = %q", reports[1].Remediation)
+	}
+
+	data, err := json.Marshal(reports[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		DurationMS int64 `json:"duration_ms"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.DurationMS < 100 {
+		t.Errorf("JSON report = %s, want duration_ms of at least 100", data)
+	}
+}
+
+func TestCheckRegistryRejectsDuplicates(t *testing.T) {
+	r := profile.NewCheckRegistry()
+	check := profile.Check{Name: "api", Run: func(context.Context) profile.CheckResult { return profile.CheckPassed("") }}
+	r.Register(check)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for a duplicate check name")
+		}
+	}()
+	r.Register(check)
+}
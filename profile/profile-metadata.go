@@ -1,9 +1,15 @@
 package profile
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"runtime/debug"
+	"sync"
+	"time"
 
+	"github.com/ikaitla/framework/ui"
 	"github.com/ikaitla/framework/ui/theme"
 	"github.com/spf13/cobra"
 )
@@ -25,6 +31,10 @@ type ProfileMetadata struct {
 	License    string
 	Contact    string
 	ConfigPath string
+
+	// GracePeriod is how long commands may take to stop after Ctrl-C
+	// before a forced exit; DefaultGracePeriod when zero
+	GracePeriod time.Duration
 }
 
 // Brand contains visual identity information
@@ -34,6 +44,15 @@ type Brand struct {
 	Color   theme.Token
 }
 
+// Definition pairs a profile's metadata with its root command constructor
+type Definition struct {
+	Metadata ProfileMetadata
+	NewRoot  func() *cobra.Command
+
+	// Commands are the profile's own constructors, without shared commands
+	Commands []func() *cobra.Command
+}
+
 
 // NewRootCommand creates the root cobra command for a profile
 func NewRootCommand(meta ProfileMetadata) *cobra.Command {
@@ -44,21 +63,99 @@ func NewRootCommand(meta ProfileMetadata) *cobra.Command {
 		Version: meta.Version,
 		Aliases: meta.Aliases,
 		Hidden:  meta.Hidden,
+
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return ApplyGlobalFlags(cmd)
+		},
+
+		// Errors are rendered by RunProfile
+		SilenceErrors: true,
+		SilenceUsage:  true,
 	}
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return WrapError(CategoryUsage, err)
+	})
 
 	// Add global flags
-	cmd.PersistentFlags().StringP("output", "o", "text", "Output format (text|json|yaml)")
-	cmd.PersistentFlags().BoolP("verbose", "v", false, "Verbose output")
-	cmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
+	addGlobalFlags(cmd)
 
+	rootMetadata.Store(cmd, meta)
 	return cmd
 }
 
-// ExecuteProfile runs the profile's root command
+// rootMetadata remembers the metadata of every root built by NewRootCommand
+var rootMetadata sync.Map
+
+// MetadataOf returns the metadata of the profile cmd belongs to
+func MetadataOf(cmd *cobra.Command) (ProfileMetadata, bool) {
+	meta, ok := rootMetadata.Load(cmd.Root())
+	if !ok {
+		return ProfileMetadata{}, false
+	}
+	return meta.(ProfileMetadata), true
+}
+
+// ExecuteProfile runs the profile's root command and exits with RunProfile's code
 func ExecuteProfile(root *cobra.Command) {
-	if err := root.Execute(); err != nil {
-		os.Exit(1)
+	if code := RunProfile(root); code != ExitOK {
+		os.Exit(code)
+	}
+}
+
+// RunProfile runs the root command and returns the process exit code.
+// Errors are reported through ui.Fail; an *Error chooses the exit code,
+// hint and docs link, anything else exits with ExitError. A panic is
+// recovered, saved as a crash report and exits with ExitInternal.
+//
+// Commands receive a context (cmd.Context()) cancelled by SIGINT or
+// SIGTERM; a command stopped that way exits with ExitInterrupted.
+//
+// Arguments cobra rejects before the global flags apply, such as an unknown
+// command or flag, are still reported in the format --output names in
+// os.Args.
+func RunProfile(root *cobra.Command) (code int) {
+	return runProfile(root, os.Args[1:])
+}
+
+// runProfile is RunProfile reading --output from args when cobra rejects
+// them
+func runProfile(root *cobra.Command, args []string) (code int) {
+	root.SilenceErrors = true
+	root.SilenceUsage = true
+
+	meta, _ := MetadataOf(root)
+	grace := meta.GracePeriod
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	ctx, interrupted, stop := signalContext(grace, ui.Stderr())
+	defer stop()
+
+	defer func() {
+		if r := recover(); r != nil {
+			ui.ClearLive()
+			code = recoverPanic(root, r, debug.Stack())
+		}
+	}()
+
+	cmd, err := root.ExecuteContextC(ctx)
+	if err == nil {
+		return ExitOK
+	}
+	ui.ClearLive()
+	if interrupted() && errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+	var e *Error
+	if errors.As(err, &e) && e.Silent {
+		return e.ExitCode()
+	}
+	if isCobraUsageError(err) {
+		applyOutputFlag(args)
 	}
+	f := failure(cmd, err)
+	ui.Fail(f)
+	return f.Code
 }
 
 // buildLongDescription constructs the full long description
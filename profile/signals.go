@@ -0,0 +1,80 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ikaitla/framework/ui"
+)
+
+// DefaultGracePeriod is how long a command may take to stop after the
+// first interrupt when ProfileMetadata.GracePeriod is not set
+const DefaultGracePeriod = 5 * time.Second
+
+// ExitInterrupted is the exit code after SIGINT or SIGTERM (128 + SIGINT)
+const ExitInterrupted = 130
+
+// signalContext returns a context cancelled by the first SIGINT or SIGTERM.
+// Live output is cleared at once; a second signal, or the grace period
+// running out, exits with ExitInterrupted. interrupted reports whether a
+// signal arrived. The notice goes to stderr as plain text: the ui settings
+// are still being applied by the command and must not be read here.
+func signalContext(grace time.Duration, stderr io.Writer) (ctx context.Context, interrupted func() bool, stop func()) {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ctx, interrupted, stopWatching := watchSignals(signals, grace, stderr, os.Exit)
+	return ctx, interrupted, func() {
+		signal.Stop(signals)
+		stopWatching()
+	}
+}
+
+// watchSignals implements signalContext for any signal source; exit ends
+// the process when a command ignores cancellation
+func watchSignals(signals <-chan os.Signal, grace time.Duration, stderr io.Writer, exit func(int)) (ctx context.Context, interrupted func() bool, stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	got := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-signals:
+		case <-done:
+			return
+		}
+		close(got)
+		ui.ClearLive()
+		fmt.Fprintln(stderr, "Interrupted, stopping (press Ctrl-C again to force)")
+		cancel()
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+		select {
+		case <-signals:
+		case <-timer.C:
+		case <-done:
+			return
+		}
+		ui.ClearLive()
+		exit(ExitInterrupted)
+	}()
+
+	interrupted = func() bool {
+		select {
+		case <-got:
+			return true
+		default:
+			return false
+		}
+	}
+	stop = func() {
+		close(done)
+		cancel()
+	}
+	return ctx, interrupted, stop
+}
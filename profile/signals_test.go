@@ -0,0 +1,119 @@
+package profile_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ikaitla/framework/profile"
+	"github.com/ikaitla/framework/ui"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/spf13/cobra"
+)
+
+func TestRunProfileCancelsOnInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("cannot send os.Interrupt on windows")
+	}
+	var stderr bytes.Buffer
+	ui.SetOutput(&bytes.Buffer{}, &stderr)
+	ui.SetColorMode(term.ColorNever)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		ui.SetColorMode(term.ColorAuto)
+	})
+
+	root := profile.NewRootCommand(profile.ProfileMetadata{Name: "demo"})
+	root.AddCommand(&cobra.Command{Use: "wait", RunE: func(cmd *cobra.Command, _ []string) error {
+		self, err := os.FindProcess(os.Getpid())
+		if err != nil {
+			return err
+		}
+		if err := self.Signal(os.Interrupt); err != nil {
+			return err
+		}
+		select {
+		case <-cmd.Context().Done():
+			return fmt.Errorf("wait: %w", cmd.Context().Err())
+		case <-time.After(5 * time.Second):
+			return errors.New("context was not cancelled")
+		}
+	}})
+	root.SetArgs([]string{"wait"})
+
+	if code := profile.RunProfile(root); code != profile.ExitInterrupted {
+		t.Fatalf("exit code = %d, want %d (stderr: %s)", code, profile.ExitInterrupted, stderr.String())
+	}
+	if got := stderr.String(); got != "Interrupted, stopping (press Ctrl-C again to force)\n" {
+		t.Fatalf("unexpected stderr: %q", got)
+	}
+}
+
+// watch starts WatchSignals with an exit function reporting its code on
+// the returned channel
+func watch(t *testing.T, grace time.Duration) (chan<- os.Signal, <-chan int) {
+	t.Helper()
+	signals := make(chan os.Signal, 2)
+	exits := make(chan int, 1)
+	ctx, interrupted, stop := profile.WatchSignals(signals, grace, io.Discard, func(code int) { exits <- code })
+	t.Cleanup(stop)
+
+	signals <- os.Interrupt
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not cancelled by the first signal")
+	}
+	if !interrupted() {
+		t.Fatal("interrupted() = false after a signal")
+	}
+	return signals, exits
+}
+
+func TestSecondSignalForcesExit(t *testing.T) {
+	signals, exits := watch(t, time.Hour)
+
+	select {
+	case code := <-exits:
+		t.Fatalf("exited with %d before the second signal", code)
+	default:
+	}
+	signals <- os.Interrupt
+	select {
+	case code := <-exits:
+		if code != profile.ExitInterrupted {
+			t.Fatalf("exit code = %d, want %d", code, profile.ExitInterrupted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal did not force an exit")
+	}
+}
+
+func TestGracePeriodForcesExit(t *testing.T) {
+	_, exits := watch(t, 10*time.Millisecond)
+
+	select {
+	case code := <-exits:
+		if code != profile.ExitInterrupted {
+			t.Fatalf("exit code = %d, want %d", code, profile.ExitInterrupted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("grace period did not force an exit")
+	}
+}
+
+func TestStopBeforeSignal(t *testing.T) {
+	signals := make(chan os.Signal, 1)
+	ctx, interrupted, stop := profile.WatchSignals(signals, time.Millisecond, io.Discard, func(code int) {
+		t.Errorf("unexpected exit %d", code)
+	})
+	stop()
+	if ctx.Err() == nil || interrupted() {
+		t.Fatalf("after stop: ctx.Err() = %v, interrupted = %v", ctx.Err(), interrupted())
+	}
+}
@@ -0,0 +1,212 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/ikaitla/framework/ui/term"
+)
+
+// Rule is a horizontal line of a table: its line string repeated over each
+// column, with junction strings at the edges and between columns.
+// An empty Line means the rule is not drawn.
+type Rule struct {
+	Left, Line, Cross, Right string
+}
+
+// BorderStyle describes the lines drawn around and inside a table.
+type BorderStyle struct {
+	Name string
+
+	Top, Header, Row, Bottom Rule
+
+	// Vertical separates columns and closes both edges. When empty, columns
+	// are separated by a two-space gap and rules have no junctions.
+	Vertical string
+}
+
+var (
+	// BorderNone draws no lines at all.
+	BorderNone = BorderStyle{Name: "none"}
+
+	// BorderSimple underlines the header; it is the default.
+	BorderSimple = BorderStyle{
+		Name:   "simple",
+		Header: Rule{Line: "─"},
+		Row:    Rule{Line: "─"},
+	}
+
+	BorderASCII = BorderStyle{
+		Name:     "ascii",
+		Top:      Rule{"+", "-", "+", "+"},
+		Header:   Rule{"+", "-", "+", "+"},
+		Row:      Rule{"+", "-", "+", "+"},
+		Bottom:   Rule{"+", "-", "+", "+"},
+		Vertical: "|",
+	}
+
+	BorderLight = BorderStyle{
+		Name:     "light",
+		Top:      Rule{"┌", "─", "┬", "┐"},
+		Header:   Rule{"├", "─", "┼", "┤"},
+		Row:      Rule{"├", "─", "┼", "┤"},
+		Bottom:   Rule{"└", "─", "┴", "┘"},
+		Vertical: "│",
+	}
+
+	BorderRounded = BorderStyle{
+		Name:     "rounded",
+		Top:      Rule{"╭", "─", "┬", "╮"},
+		Header:   Rule{"├", "─", "┼", "┤"},
+		Row:      Rule{"├", "─", "┼", "┤"},
+		Bottom:   Rule{"╰", "─", "┴", "╯"},
+		Vertical: "│",
+	}
+
+	BorderHeavy = BorderStyle{
+		Name:     "heavy",
+		Top:      Rule{"┏", "━", "┳", "┓"},
+		Header:   Rule{"┣", "━", "╋", "┫"},
+		Row:      Rule{"┣", "━", "╋", "┫"},
+		Bottom:   Rule{"┗", "━", "┻", "┛"},
+		Vertical: "┃",
+	}
+
+	BorderDouble = BorderStyle{
+		Name:     "double",
+		Top:      Rule{"╔", "═", "╦", "╗"},
+		Header:   Rule{"╠", "═", "╬", "╣"},
+		Row:      Rule{"╠", "═", "╬", "╣"},
+		Bottom:   Rule{"╚", "═", "╩", "╝"},
+		Vertical: "║",
+	}
+
+	// BorderMarkdown renders text that is also a GitHub-flavored Markdown table.
+	BorderMarkdown = BorderStyle{
+		Name:     "markdown",
+		Header:   Rule{"|", "-", "|", "|"},
+		Vertical: "|",
+	}
+)
+
+// BorderStyles lists the predefined styles.
+func BorderStyles() []BorderStyle {
+	return []BorderStyle{
+		BorderNone, BorderSimple, BorderASCII, BorderLight,
+		BorderRounded, BorderHeavy, BorderDouble, BorderMarkdown,
+	}
+}
+
+// BorderStyleByName finds a predefined style, e.g. from a flag value.
+func BorderStyleByName(name string) (BorderStyle, bool) {
+	for _, s := range BorderStyles() {
+		if strings.EqualFold(s.Name, name) {
+			return s, true
+		}
+	}
+	return BorderStyle{}, false
+}
+
+// boxed reports whether columns are separated by vertical lines.
+func (s BorderStyle) boxed() bool {
+	return s.Vertical != ""
+}
+
+// markdown reports whether cells must be escaped as Markdown.
+func (s BorderStyle) markdown() bool {
+	return s.Name == BorderMarkdown.Name
+}
+
+// unicode reports whether the style draws anything outside ASCII.
+func (s BorderStyle) unicode() bool {
+	for _, part := range s.parts() {
+		for _, r := range *part {
+			if r > 0x7f {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ascii returns the style with every box-drawing character replaced by
+// its closest ASCII equivalent.
+func (s BorderStyle) ascii() BorderStyle {
+	for _, part := range s.parts() {
+		*part = asciiBox.Replace(*part)
+	}
+	return s
+}
+
+func (s *BorderStyle) parts() []*string {
+	var parts []*string
+	for _, r := range []*Rule{&s.Top, &s.Header, &s.Row, &s.Bottom} {
+		parts = append(parts, &r.Left, &r.Line, &r.Cross, &r.Right)
+	}
+	return append(parts, &s.Vertical)
+}
+
+var asciiBox = strings.NewReplacer(
+	"─", "-", "━", "-", "═", "-",
+	"│", "|", "┃", "|", "║", "|",
+	"┌", "+", "┬", "+", "┐", "+", "├", "+", "┼", "+", "┤", "+", "└", "+", "┴", "+", "┘", "+",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"┏", "+", "┳", "+", "┓", "+", "┣", "+", "╋", "+", "┫", "+", "┗", "+", "┻", "+", "┛", "+",
+	"╔", "+", "╦", "+", "╗", "+", "╠", "+", "╬", "+", "╣", "+", "╚", "+", "╩", "+", "╝", "+",
+)
+
+// overhead is the number of columns taken by borders and gaps.
+func (s BorderStyle) overhead(columns int) int {
+	if columns == 0 {
+		return 0
+	}
+	if s.boxed() {
+		return 3*columns + 1
+	}
+	return columnGap * (columns - 1)
+}
+
+// line joins already fitted cells.
+func (s BorderStyle) line(cells []string, widths []int, aligns []Align) string {
+	if !s.boxed() {
+		return joinCells(cells, widths, aligns)
+	}
+	var b strings.Builder
+	b.WriteString(s.Vertical)
+	for k, cell := range cells {
+		b.WriteString(" ")
+		b.WriteString(align(cell, widths[k], aligns[k]))
+		b.WriteString(" ")
+		b.WriteString(s.Vertical)
+	}
+	return b.String()
+}
+
+// rule draws r over the columns; ok is false when r is not drawn.
+func (s BorderStyle) rule(r Rule, widths []int) (string, bool) {
+	if r.Line == "" || len(widths) == 0 {
+		return "", false
+	}
+	if !s.boxed() {
+		segments := make([]string, len(widths))
+		for k, w := range widths {
+			segments[k] = strings.Repeat(r.Line, w)
+		}
+		return strings.Join(segments, strings.Repeat(" ", columnGap)), true
+	}
+
+	var b strings.Builder
+	b.WriteString(r.Left)
+	for k, w := range widths {
+		if k > 0 {
+			b.WriteString(r.Cross)
+		}
+		b.WriteString(strings.Repeat(r.Line, w+2))
+	}
+	b.WriteString(r.Right)
+	return b.String(), true
+}
+
+// centered pads s on both sides to width columns.
+func centered(s string, width int) string {
+	return strings.TrimRight(align(term.TruncateWidth(s, width, ellipsis), width, AlignCenter), " ")
+}
@@ -3,27 +3,34 @@ package components
 import (
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
 	"github.com/ikaitla/framework/ui/theme"
 )
 
 func RenderKeyValue(out *output.Output, pairs map[string]string) {
+	if out.Format != output.Text {
+		if err := out.Print(pairs); err != nil {
+			out.Error("render key/value pairs as %s: %v", out.Format, err)
+		}
+		return
+	}
+
 	// stable order
 	keys := make([]string, 0, len(pairs))
 	maxKeyLen := 0
 	for k := range pairs {
 		keys = append(keys, k)
-		if len(k) > maxKeyLen {
-			maxKeyLen = len(k)
+		if w := term.StringWidth(k); w > maxKeyLen {
+			maxKeyLen = w
 		}
 	}
 	sort.Strings(keys)
 
 	for _, k := range keys {
 		v := pairs[k]
-		key := k + strings.Repeat(" ", maxKeyLen-len(k))
+		key := term.PadWidth(k, maxKeyLen)
 		if out.ColorsEnabled() {
 			out.Printf("%s: %s", out.Stylize(key, theme.Slate900, theme.Bold), v)
 		} else {
@@ -33,18 +40,25 @@ func RenderKeyValue(out *output.Output, pairs map[string]string) {
 }
 
 func RenderKeyValueAny(out *output.Output, pairs map[string]any) {
+	if out.Format != output.Text {
+		if err := out.Print(pairs); err != nil {
+			out.Error("render key/value pairs as %s: %v", out.Format, err)
+		}
+		return
+	}
+
 	keys := make([]string, 0, len(pairs))
 	maxKeyLen := 0
 	for k := range pairs {
 		keys = append(keys, k)
-		if len(k) > maxKeyLen {
-			maxKeyLen = len(k)
+		if w := term.StringWidth(k); w > maxKeyLen {
+			maxKeyLen = w
 		}
 	}
 	sort.Strings(keys)
 
 	for _, k := range keys {
-		key := k + strings.Repeat(" ", maxKeyLen-len(k))
+		key := term.PadWidth(k, maxKeyLen)
 		v := fmt.Sprintf("%v", pairs[k])
 		if out.ColorsEnabled() {
 			out.Printf("%s: %s", out.Stylize(key, theme.Slate900, theme.Bold), v)
@@ -0,0 +1,145 @@
+package components
+
+import (
+	"strings"
+
+	"github.com/ikaitla/framework/ui/term"
+)
+
+const (
+	columnGap      = 2
+	minColumnWidth = 4
+	ellipsis       = "…"
+)
+
+// Overflow decides what happens to cells wider than their column.
+type Overflow int
+
+const (
+	// Truncate cuts the cell and ends it with an ellipsis.
+	Truncate Overflow = iota
+	// Wrap breaks the cell over several lines at word boundaries.
+	Wrap
+)
+
+// Align positions a cell within its column.
+type Align int
+
+const (
+	// AlignAuto aligns numeric columns right and everything else left.
+	AlignAuto Align = iota
+	AlignLeft
+	AlignRight
+	AlignCenter
+)
+
+// Column holds the layout settings of one table column.
+type Column struct {
+	// Align applies to the header and every cell of the column.
+	Align Align
+	// MaxWidth caps the column width; 0 means no cap.
+	MaxWidth int
+	// Overflow applies to cells wider than the final column width.
+	Overflow Overflow
+	// Priority 0 columns are always shown. When the table does not fit,
+	// columns are dropped starting with the highest Priority.
+	Priority int
+}
+
+// layout picks the visible columns and their widths for the available space,
+// starting from the natural width of each column.
+func (t *Table) layout(style BorderStyle, natural []int) ([]int, []int) {
+	visible := make([]int, len(t.headers))
+	widths := make([]int, len(t.headers))
+	for i := range t.headers {
+		visible[i] = i
+		widths[i] = natural[i]
+		if max := t.columns[i].MaxWidth; max > 0 && widths[i] > max {
+			widths[i] = max
+		}
+	}
+
+	avail := t.availableWidth()
+	if avail <= 0 {
+		return visible, widths
+	}
+
+	total := func(widths []int) int {
+		sum := style.overhead(len(widths))
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+
+	for total(widths) > avail {
+		drop := -1
+		for k, i := range visible {
+			p := t.columns[i].Priority
+			if p > 0 && (drop < 0 || p >= t.columns[visible[drop]].Priority) {
+				drop = k
+			}
+		}
+		if drop < 0 {
+			break
+		}
+		visible = append(visible[:drop], visible[drop+1:]...)
+		widths = append(widths[:drop], widths[drop+1:]...)
+	}
+
+	// Still too wide: take one column at a time from the widest cell.
+	for excess := total(widths) - avail; excess > 0; excess-- {
+		widest := 0
+		for k := range widths {
+			if widths[k] > widths[widest] {
+				widest = k
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+
+	return visible, widths
+}
+
+func (t *Table) availableWidth() int {
+	switch {
+	case t.maxWidth > 0:
+		return t.maxWidth
+	case t.maxWidth < 0:
+		return 0
+	}
+	return term.Width(t.out.Out)
+}
+
+// align pads s to width columns according to a.
+func align(s string, width int, a Align) string {
+	gap := width - term.StringWidth(s)
+	if gap <= 0 {
+		return s
+	}
+	switch a {
+	case AlignRight:
+		return strings.Repeat(" ", gap) + s
+	case AlignCenter:
+		left := gap / 2
+		return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+	}
+	return s + strings.Repeat(" ", gap)
+}
+
+// fitCell returns the lines of s that fit in width columns.
+func fitCell(s string, width int, overflow Overflow) []string {
+	if term.StringWidth(s) <= width && !strings.Contains(s, "\n") {
+		return []string{s}
+	}
+	if overflow == Wrap {
+		return term.WrapWidth(s, width)
+	}
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i] + " " + ellipsis
+	}
+	return []string{term.TruncateWidth(s, width, ellipsis)}
+}
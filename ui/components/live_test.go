@@ -0,0 +1,114 @@
+package components_test
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ikaitla/framework/ui/components"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+)
+
+// frameWriter signals the first frame drawn and the first line erased
+type frameWriter struct {
+	bytes.Buffer
+	drawn, erased         chan struct{}
+	drawnOnce, erasedOnce sync.Once
+}
+
+func newFrameWriter() *frameWriter {
+	return &frameWriter{drawn: make(chan struct{}), erased: make(chan struct{})}
+}
+
+func (w *frameWriter) Write(p []byte) (int, error) {
+	n, err := w.Buffer.Write(p)
+	if strings.Trim(string(p), "\r ") == "" {
+		w.erasedOnce.Do(func() { close(w.erased) })
+	} else {
+		w.drawnOnce.Do(func() { close(w.drawn) })
+	}
+	return n, err
+}
+
+func wait(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
+
+func TestProgressBarIsClearedOnCancel(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	out.ColorMode = term.ColorAlways
+
+	bar := components.NewProgressBar(out, 10, "sync")
+	bar.Update(5)
+	drawn := buf.Len()
+	out.ClearLive()
+
+	cleared := buf.String()[drawn:]
+	if strings.Trim(cleared, "\r ") != "" || !strings.HasPrefix(cleared, "\r") || !strings.HasSuffix(cleared, "\r") {
+		t.Fatalf("progress bar not erased: %q", cleared)
+	}
+
+	after := buf.Len()
+	bar.Increment()
+	bar.Finish()
+	if buf.Len() != after {
+		t.Fatalf("progress bar drew after Clear: %q", buf.String()[after:])
+	}
+}
+
+func TestSpinnerIsClearedOnCancel(t *testing.T) {
+	out, _ := newOutput(output.Text)
+	out.ColorMode = term.ColorAlways
+	w := newFrameWriter()
+	out.Out = w
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	spinner := components.NewSpinner(out, "waiting")
+	spinner.Start()
+	spinner.Watch(ctx)
+	wait(t, w.drawn, "the first frame")
+
+	cancel()
+	wait(t, w.erased, "the spinner to be erased")
+	spinner.Stop(true)
+
+	if got := w.String(); strings.Contains(got, "[✓]") || !strings.HasSuffix(got, "\r") {
+		t.Fatalf("spinner not cleared on cancel: %q", got)
+	}
+}
+
+func TestWatchEndsWithStop(t *testing.T) {
+	out, _ := newOutput(output.Text)
+	before := runtime.NumGoroutine()
+
+	ctx := context.Background()
+	for range 50 {
+		spinner := components.NewSpinner(out, "waiting")
+		spinner.Watch(ctx)
+		spinner.Start()
+		spinner.Stop(true)
+
+		bar := components.NewProgressBar(out, 1, "sync")
+		bar.Watch(ctx)
+		bar.Finish()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > before+10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("watchers leaked: %d goroutines, %d before", runtime.NumGoroutine(), before)
+		}
+		runtime.Gosched()
+	}
+}
@@ -1,10 +1,13 @@
 package components
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
 	"github.com/ikaitla/framework/ui/theme"
 )
 
@@ -14,6 +17,11 @@ type ProgressBar struct {
 	current int
 	width   int
 	prefix  string
+
+	mu      sync.Mutex
+	drawn   int // width of the last line written, 0 before the first
+	cleared bool
+	unwatch chan struct{} // closed by Finish and Clear to end Watch
 }
 
 func NewProgressBar(out *output.Output, total int, prefix string) *ProgressBar {
@@ -26,25 +34,81 @@ func NewProgressBar(out *output.Output, total int, prefix string) *ProgressBar {
 }
 
 func (p *ProgressBar) Update(current int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.current = current
 	p.render()
 }
 
 func (p *ProgressBar) Increment() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.current++
 	p.render()
 }
 
 func (p *ProgressBar) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cleared {
+		return
+	}
 	p.current = p.total
 	p.render()
+	p.endWatch()
+	p.out.RemoveLive(p)
 	p.out.Printf("") // newline
 }
 
+// Watch clears the bar when ctx is cancelled, e.g. by Ctrl-C. The watch
+// ends with Finish or Clear.
+func (p *ProgressBar) Watch(ctx context.Context) {
+	p.mu.Lock()
+	if p.unwatch == nil {
+		p.unwatch = make(chan struct{})
+	}
+	unwatch := p.unwatch
+	p.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			p.Clear()
+		case <-unwatch:
+		}
+	}()
+}
+
+// Clear erases the bar and ignores later updates.
+func (p *ProgressBar) Clear() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cleared {
+		return
+	}
+	p.cleared = true
+	p.endWatch()
+	p.out.RemoveLive(p)
+	if p.drawn > 0 {
+		fmt.Fprint(p.out.Out, "\r"+strings.Repeat(" ", p.drawn)+"\r")
+	}
+}
+
+// endWatch stops the Watch goroutine; p.mu must be held.
+func (p *ProgressBar) endWatch() {
+	if p.unwatch != nil {
+		close(p.unwatch)
+		p.unwatch = nil
+	}
+}
+
 func (p *ProgressBar) render() {
-	if !p.out.ColorsEnabled() {
+	if !p.out.ColorsEnabled() || p.cleared {
 		return
 	}
+	if p.drawn == 0 {
+		p.out.AddLive(p)
+	}
 
 	if p.total <= 0 {
 		p.total = 1
@@ -63,11 +127,13 @@ func (p *ProgressBar) render() {
 	bar := strings.Repeat("█", filled) + strings.Repeat("░", empty)
 	percentStr := fmt.Sprintf("%.0f%%", percent*100)
 
-	fmt.Fprintf(p.out.Out, "\r%s [%s] %s %d/%d",
+	line := fmt.Sprintf("%s [%s] %s %d/%d",
 		p.prefix,
 		p.out.Stylize(bar, theme.Info600),
 		percentStr,
 		p.current,
 		p.total,
 	)
+	p.drawn = max(p.drawn, term.StringWidth(line))
+	fmt.Fprintf(p.out.Out, "\r%s", line)
 }
@@ -0,0 +1,150 @@
+package components
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ikaitla/framework/ui/term"
+)
+
+// Compare orders two cells, returning a negative number, zero or a positive
+// number like strings.Compare.
+type Compare func(a, b string) int
+
+// CompareText orders cells lexically, ignoring escape sequences.
+func CompareText(a, b string) int {
+	return strings.Compare(term.StripANSI(a), term.StripANSI(b))
+}
+
+// CompareNumeric orders cells as numbers ("1,024", "12.5", "40%").
+// Cells that are not numbers sort after numbers, lexically.
+func CompareNumeric(a, b string) int {
+	x, okA := parseNumber(a)
+	y, okB := parseNumber(b)
+	switch {
+	case okA && okB:
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return CompareText(a, b)
+}
+
+// CompareSemver orders cells as semantic versions ("v1.10.0" after "v1.9.2",
+// "1.0.0-rc.1" before "1.0.0"). Cells that are not versions sort after
+// versions, lexically.
+func CompareSemver(a, b string) int {
+	x, okA := parseSemver(a)
+	y, okB := parseSemver(b)
+	switch {
+	case okA && okB:
+		return x.compare(y)
+	case okA:
+		return -1
+	case okB:
+		return 1
+	}
+	return CompareText(a, b)
+}
+
+// Descending reverses cmp.
+func Descending(cmp Compare) Compare {
+	return func(a, b string) int { return cmp(b, a) }
+}
+
+func parseNumber(s string) (float64, bool) {
+	s = strings.TrimSpace(term.StripANSI(s))
+	s = strings.TrimSuffix(s, "%")
+	s = strings.ReplaceAll(s, ",", "")
+	// plain decimals only: ParseFloat also takes "inf", "nan" and hex
+	if s == "" || strings.ContainsFunc(s, func(r rune) bool { return !strings.ContainsRune("0123456789.+-eE", r) }) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+type semver struct {
+	core [3]int
+	pre  []string
+}
+
+func parseSemver(s string) (semver, bool) {
+	var v semver
+	s = strings.TrimSpace(term.StripANSI(s))
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		v.pre = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) == 0 || len(parts) > 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
+
+// compare follows semver precedence: the core first, then a release ranks
+// above its pre-releases, which compare identifier by identifier.
+func (v semver) compare(o semver) int {
+	for i := range v.core {
+		if v.core[i] != o.core[i] {
+			if v.core[i] < o.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case len(v.pre) == 0 && len(o.pre) == 0:
+		return 0
+	case len(v.pre) == 0:
+		return 1
+	case len(o.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.pre) && i < len(o.pre); i++ {
+		a, b := v.pre[i], o.pre[i]
+		x, errA := strconv.Atoi(a)
+		y, errB := strconv.Atoi(b)
+		switch {
+		case errA == nil && errB == nil:
+			if x != y {
+				if x < y {
+					return -1
+				}
+				return 1
+			}
+		case errA == nil:
+			return -1
+		case errB == nil:
+			return 1
+		default:
+			if c := strings.Compare(a, b); c != 0 {
+				return c
+			}
+		}
+	}
+	return len(v.pre) - len(o.pre)
+}
@@ -1,12 +1,14 @@
 package components
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
 	"github.com/ikaitla/framework/ui/theme"
 )
 
@@ -14,18 +16,19 @@ type Spinner struct {
 	out     *output.Output
 	message string
 
-	mu     sync.Mutex
-	active bool
-	done   chan struct{}
-	frames []string
-	i      int
+	mu      sync.Mutex
+	active  bool
+	stop    chan struct{}
+	done    chan struct{}
+	unwatch chan struct{} // closed by Stop and Clear to end Watch
+	frames  []string
+	i       int
 }
 
 func NewSpinner(out *output.Output, message string) *Spinner {
 	return &Spinner{
 		out:     out,
 		message: message,
-		done:    make(chan struct{}),
 		frames:  []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"},
 	}
 }
@@ -42,29 +45,39 @@ func (s *Spinner) Start() {
 		return
 	}
 	s.active = true
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	stop, done := s.stop, s.done
 	s.mu.Unlock()
 
-	go s.animate()
+	s.out.AddLive(s)
+	go s.animate(stop, done)
 }
 
-func (s *Spinner) Stop(success bool) {
-	if !s.out.ColorsEnabled() {
-		return
-	}
-
+// Watch clears the spinner when ctx is cancelled, e.g. by Ctrl-C, so the
+// command can return without leaving a half-drawn line. The watch ends with
+// Stop or Clear.
+func (s *Spinner) Watch(ctx context.Context) {
 	s.mu.Lock()
-	if !s.active {
-		s.mu.Unlock()
-		return
+	if s.unwatch == nil {
+		s.unwatch = make(chan struct{})
 	}
-	s.active = false
+	unwatch := s.unwatch
 	s.mu.Unlock()
 
-	<-s.done
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.Clear()
+		case <-unwatch:
+		}
+	}()
+}
 
-	// clear line
-	clear := "\r" + strings.Repeat(" ", len(s.message)+12) + "\r"
-	fmt.Fprint(s.out.Out, clear)
+func (s *Spinner) Stop(success bool) {
+	if !s.halt() {
+		return
+	}
 
 	if success {
 		fmt.Fprintf(s.out.Out, "%s %s\n", s.out.Stylize("[✓]", theme.Success600), s.message)
@@ -73,31 +86,64 @@ func (s *Spinner) Stop(success bool) {
 	}
 }
 
+// Clear stops the spinner and erases its line without printing a result.
+func (s *Spinner) Clear() {
+	s.halt()
+}
+
+// halt stops the animation and waits until its line is erased. It reports
+// whether this call stopped a running spinner.
+func (s *Spinner) halt() bool {
+	s.mu.Lock()
+	stopped := s.active
+	if s.active {
+		s.active = false
+		close(s.stop)
+	}
+	done := s.done
+	if s.unwatch != nil {
+		close(s.unwatch)
+		s.unwatch = nil
+	}
+	s.mu.Unlock()
+
+	if done != nil {
+		<-done
+	}
+	if stopped {
+		s.out.RemoveLive(s)
+	}
+	return stopped
+}
+
 func (s *Spinner) UpdateMessage(message string) {
 	s.mu.Lock()
 	s.message = message
 	s.mu.Unlock()
 }
 
-func (s *Spinner) animate() {
+func (s *Spinner) animate(stop <-chan struct{}, done chan<- struct{}) {
 	t := time.NewTicker(80 * time.Millisecond)
 	defer t.Stop()
+	defer close(done)
 
+	drawn := 0
 	for {
 		s.mu.Lock()
-		active := s.active
 		msg := s.message
 		frame := s.frames[s.i]
 		s.i = (s.i + 1) % len(s.frames)
 		s.mu.Unlock()
 
-		if !active {
-			break
-		}
+		line := s.out.Stylize(frame, theme.Info600) + " " + msg
+		drawn = max(drawn, term.StringWidth(line))
+		fmt.Fprintf(s.out.Out, "\r%s", line)
 
-		fmt.Fprintf(s.out.Out, "\r%s %s", s.out.Stylize(frame, theme.Info600), msg)
-		<-t.C
+		select {
+		case <-t.C:
+		case <-stop:
+			fmt.Fprint(s.out.Out, "\r"+strings.Repeat(" ", drawn)+"\r")
+			return
+		}
 	}
-
-	close(s.done)
 }
@@ -0,0 +1,142 @@
+package components
+
+import (
+	"github.com/ikaitla/framework/ui/output"
+)
+
+// DefaultSampleSize is the number of rows a StreamTable buffers to size its
+// columns before it starts printing.
+const DefaultSampleSize = 100
+
+// StreamTable prints rows as they are added instead of holding them all
+// until Render. In text mode column widths come from the first rows (the
+// sample) or from SetWidths; later rows that do not fit are truncated or
+// wrapped like any other cell. Other formats are streamed through
+// output.RecordWriter, so JSON becomes newline-delimited objects.
+// --query and --fields need every row, so a filtered table is buffered and
+// printed by Close.
+type StreamTable struct {
+	table  *Table
+	sample int
+
+	layout  textLayout
+	started bool
+	rows    int
+
+	records *output.RecordWriter
+}
+
+// NewStreamTable creates a streaming table. It accepts the same column,
+// border, title and footer settings as Table; sorting needs every row and
+// is not available.
+func NewStreamTable(out *output.Output, headers ...string) *StreamTable {
+	return &StreamTable{
+		table:  NewTable(out, headers...),
+		sample: DefaultSampleSize,
+	}
+}
+
+// SetSampleSize sets how many rows are buffered to compute widths.
+func (s *StreamTable) SetSampleSize(n int) {
+	s.sample = n
+}
+
+// SetWidths fixes the column widths so rows print immediately.
+func (s *StreamTable) SetWidths(widths ...int) {
+	for i := range s.table.widths {
+		if i < len(widths) && widths[i] > 0 {
+			s.table.widths[i] = widths[i]
+			s.table.columns[i].MaxWidth = widths[i]
+		}
+	}
+	s.sample = 0
+}
+
+func (s *StreamTable) SetColumn(i int, c Column)   { s.table.SetColumn(i, c) }
+func (s *StreamTable) SetMaxWidth(width int)       { s.table.SetMaxWidth(width) }
+func (s *StreamTable) SetBorder(style BorderStyle) { s.table.SetBorder(style) }
+func (s *StreamTable) SetTitle(title string)       { s.table.SetTitle(title) }
+func (s *StreamTable) SetFooter(cells ...string)   { s.table.SetFooter(cells...) }
+func (s *StreamTable) SetRowSeparators(on bool)    { s.table.SetRowSeparators(on) }
+
+// AddRow prints the row, or buffers it while the sample is being collected.
+func (s *StreamTable) AddRow(cells ...string) {
+	t := s.table
+	if len(t.headers) == 0 {
+		return
+	}
+
+	if t.out.Format != output.Text {
+		if s.records == nil {
+			s.records = t.out.NewRecordWriter(t.headers...)
+		}
+		if err := s.records.Write(cells); err != nil {
+			t.out.Error("write table row as %s: %v", t.out.Format, err)
+		}
+		return
+	}
+
+	if s.started {
+		s.printRow(cells)
+		return
+	}
+
+	t.AddRow(cells...)
+	if len(t.rows) >= s.sample && !t.out.Filtering() {
+		s.Flush()
+	}
+}
+
+// Flush sizes the columns from the rows buffered so far and prints them.
+// Call it to show partial results before the sample is complete.
+func (s *StreamTable) Flush() {
+	t := s.table
+	if s.started || t.out.Format != output.Text || len(t.headers) == 0 || t.out.Filtering() {
+		return
+	}
+	s.started = true
+
+	s.layout = t.prepare()
+	s.layout.begin(t)
+	for _, row := range t.rows {
+		s.layout.row(t, row, s.rows)
+		s.rows++
+	}
+	t.rows = nil
+}
+
+// Close prints any buffered rows and the footer, or ends the stream in
+// structured formats.
+func (s *StreamTable) Close() {
+	t := s.table
+	if len(t.headers) == 0 {
+		return
+	}
+
+	if t.out.Format != output.Text {
+		if s.records == nil {
+			s.records = t.out.NewRecordWriter(t.headers...)
+		}
+		if err := s.records.Close(); err != nil {
+			t.out.Error("finish table as %s: %v", t.out.Format, err)
+		}
+		return
+	}
+
+	if t.out.Filtering() {
+		t.Render()
+		return
+	}
+	s.Flush()
+	s.layout.end(t)
+}
+
+func (s *StreamTable) printRow(cells []string) {
+	t := s.table
+	row := make([]string, len(t.headers))
+	for i := 0; i < len(row) && i < len(cells); i++ {
+		row[i] = cells[i]
+	}
+	s.layout.row(t, tableRow{cells: row}, s.rows)
+	s.rows++
+}
@@ -0,0 +1,326 @@
+package components
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+// structColumn is a struct field rendered as a table column, described by
+// its `ui:"Header,align=right,format=bytes"` tag.
+type structColumn struct {
+	index     []int
+	name      string
+	header    string
+	key       string
+	omitEmpty bool
+	format    string
+	column    Column
+}
+
+// RenderStructs renders a slice of structs (or a single struct) as a table.
+//
+// Every exported field is a column unless tagged `ui:"-"`. The tag's first
+// element is the header (the field name by default), followed by options:
+// align=left|right|center, format=bytes|duration|date|time, width=N (max
+// width), wrap, and priority=N (see Column). Text, Markdown and HTML show
+// the headers and formatted cells; every other format sees objects keyed by
+// the `json` names with raw field values, as encoding/json would produce,
+// including omitempty outside of the tabular formats.
+//
+// columns selects and orders columns by header, json name or field name;
+// when empty, the columns chosen with --columns (out.Columns) apply. The
+// selection happens before output, so --query and --fields then only see
+// the selected columns.
+func RenderStructs(out *output.Output, v any, columns ...string) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+
+	var items []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			items = append(items, rv.Index(i))
+		}
+	case reflect.Struct:
+		items = []reflect.Value{rv}
+	default:
+		return fmt.Errorf("RenderStructs: expected a struct or a slice of structs, got %s", rv.Type())
+	}
+
+	elem := rv.Type()
+	if rv.Kind() != reflect.Struct {
+		elem = elem.Elem()
+	}
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("RenderStructs: expected a struct or a slice of structs, got %s", rv.Type())
+	}
+
+	cols, err := structColumns(elem)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		columns = out.Columns
+	}
+	if cols, err = selectColumns(cols, columns); err != nil {
+		return err
+	}
+
+	if keyedFormat(out.Format) {
+		// tabular formats keep every column so rows line up
+		omit := !out.Format.Tabular()
+		objs := make([]output.Object, 0, len(items))
+		for _, item := range items {
+			obj := make(output.Object, 0, len(cols))
+			for _, c := range cols {
+				if c.key == "" {
+					continue
+				}
+				var value any
+				f, ok := field(item, c.index)
+				if omit && (!ok || c.omitEmpty && isEmptyValue(f)) {
+					continue
+				}
+				if ok {
+					value = f.Interface()
+				}
+				obj = append(obj, output.Field{Key: c.key, Value: value})
+			}
+			objs = append(objs, obj)
+		}
+		return out.Print(objs)
+	}
+
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = c.header
+	}
+	table := NewTable(out, headers...)
+	for i, c := range cols {
+		table.SetColumn(i, c.column)
+	}
+	for _, item := range items {
+		cells := make([]string, len(cols))
+		for i, c := range cols {
+			if f, ok := field(item, c.index); ok {
+				cells[i] = formatValue(f, c.format)
+			}
+		}
+		table.AddRow(cells...)
+	}
+	table.Render()
+	return nil
+}
+
+// keyedFormat reports whether f renders structs as objects keyed by their
+// json names rather than as a table of headers.
+func keyedFormat(f output.Format) bool {
+	return f != output.Text && f != output.Markdown && f != output.HTML
+}
+
+func structColumns(t reflect.Type) ([]structColumn, error) {
+	var cols []structColumn
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				continue
+			}
+		}
+		tag := f.Tag.Get("ui")
+		if tag == "-" {
+			continue
+		}
+
+		c := structColumn{index: f.Index, name: f.Name, header: f.Name, key: f.Name}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			c.key = ""
+		} else if name != "" {
+			c.key = name
+		}
+		for _, opt := range strings.Split(opts, ",") {
+			c.omitEmpty = c.omitEmpty || opt == "omitempty"
+		}
+
+		parts := strings.Split(tag, ",")
+		if parts[0] != "" {
+			c.header = parts[0]
+		}
+		for _, opt := range parts[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(opt), "=")
+			var err error
+			switch key {
+			case "align":
+				c.column.Align, err = parseAlign(value)
+			case "format":
+				c.format = value
+			case "width":
+				c.column.MaxWidth, err = strconv.Atoi(value)
+			case "priority":
+				c.column.Priority, err = strconv.Atoi(value)
+			case "wrap":
+				c.column.Overflow = Wrap
+			case "":
+			default:
+				err = fmt.Errorf("unknown option %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("RenderStructs: field %s.%s: %w", t.Name(), f.Name, err)
+			}
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// selectColumns keeps the named columns, in the order they are named.
+func selectColumns(cols []structColumn, names []string) ([]structColumn, error) {
+	if len(names) == 0 {
+		return cols, nil
+	}
+	selected := make([]structColumn, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		found := false
+		for _, c := range cols {
+			if strings.EqualFold(name, c.header) || strings.EqualFold(name, c.key) || strings.EqualFold(name, c.name) {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			available := make([]string, len(cols))
+			for i, c := range cols {
+				available[i] = c.header
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
+}
+
+func parseAlign(s string) (Align, error) {
+	switch strings.ToLower(s) {
+	case "", "auto":
+		return AlignAuto, nil
+	case "left":
+		return AlignLeft, nil
+	case "right":
+		return AlignRight, nil
+	case "center":
+		return AlignCenter, nil
+	}
+	return AlignAuto, fmt.Errorf("unknown alignment %q", s)
+}
+
+// field follows index through embedded pointers; ok is false when one is nil.
+func field(v reflect.Value, index []int) (reflect.Value, bool) {
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// isEmptyValue matches the omitempty rule of encoding/json.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// formatValue renders a field as cell text.
+func formatValue(v reflect.Value, format string) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch format {
+	case "bytes":
+		switch {
+		case v.CanInt():
+			return output.HumanBytes(v.Int())
+		case v.CanUint():
+			return output.HumanBytes(int64(v.Uint()))
+		case v.CanFloat():
+			return output.HumanBytes(int64(v.Float()))
+		}
+	case "duration":
+		if v.Type() == durationType {
+			return output.HumanDuration(time.Duration(v.Int()))
+		}
+	case "date":
+		if v.Type() == timeType {
+			return formatTime(v.Interface().(time.Time), time.DateOnly)
+		}
+	case "time":
+		if v.Type() == timeType {
+			return formatTime(v.Interface().(time.Time), time.DateTime)
+		}
+	}
+
+	switch {
+	case v.Type() == timeType:
+		return formatTime(v.Interface().(time.Time), time.RFC3339)
+	case v.CanInterface():
+		if s, ok := v.Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatValue(v.Index(i), "")
+		}
+		return strings.Join(parts, ", ")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func formatTime(t time.Time, layout string) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
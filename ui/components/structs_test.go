@@ -0,0 +1,99 @@
+package components_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ikaitla/framework/ui/components"
+	"github.com/ikaitla/framework/ui/output"
+)
+
+type artifact struct {
+	Name    string        `json:"name" ui:"Artifact"`
+	Size    int64         `json:"size_bytes" ui:"Size,align=right,format=bytes"`
+	Took    time.Duration `json:"took" ui:"Build Time,format=duration"`
+	Tags    []string      `json:"tags"`
+	Private string        `json:"-" ui:"-"`
+}
+
+var artifacts = []artifact{
+	{Name: "cli", Size: 1536, Took: 1500 * time.Millisecond, Tags: []string{"linux", "amd64"}},
+	{Name: "docs", Size: 42, Took: 2 * time.Minute},
+}
+
+func TestRenderStructs_Text(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	if err := components.RenderStructs(out, artifacts); err != nil {
+		t.Fatalf("RenderStructs: %v", err)
+	}
+
+	want := "Artifact     Size  Build Time  Tags\n" +
+		"────────  ───────  ──────────  ────────────\n" +
+		"cli       1.5 KiB  1.5s        linux, amd64\n" +
+		"docs         42 B  2m0s\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderStructs_JSONColumns(t *testing.T) {
+	out, buf := newOutput(output.JSON)
+	out.Columns = []string{"size_bytes", "artifact"}
+	if err := components.RenderStructs(out, artifacts[:1]); err != nil {
+		t.Fatalf("RenderStructs: %v", err)
+	}
+
+	want := "[\n  {\n    \"size_bytes\": 1536,\n    \"name\": \"cli\"\n  }\n]\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := components.RenderStructs(out, artifacts, "nope"); err == nil {
+		t.Fatal("expected error for unknown column")
+	}
+}
+
+type owner struct {
+	Owner string `json:"owner,omitempty"`
+}
+
+type release struct {
+	*owner
+	Name  string `json:"name" ui:"Release"`
+	Notes string `json:"notes,omitempty"`
+	Draft bool   `json:"draft,omitempty"`
+}
+
+func TestRenderStructs_KeyedFormats(t *testing.T) {
+	releases := []release{
+		{owner: &owner{Owner: "ada"}, Name: "v1", Notes: "first", Draft: true},
+		{Name: "v2"},
+	}
+
+	out, buf := newOutput(output.JSON)
+	if err := components.RenderStructs(out, releases); err != nil {
+		t.Fatalf("RenderStructs: %v", err)
+	}
+	want := "[\n  {\n    \"owner\": \"ada\",\n    \"name\": \"v1\",\n    \"notes\": \"first\",\n    \"draft\": true\n  },\n  {\n    \"name\": \"v2\"\n  }\n]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("json got:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, buf = newOutput(output.CSV)
+	if err := components.RenderStructs(out, releases); err != nil {
+		t.Fatalf("RenderStructs: %v", err)
+	}
+	want = "owner,name,notes,draft\nada,v1,first,true\n,v2,,false\n"
+	if got := buf.String(); got != want {
+		t.Errorf("csv got:\n%s\nwant:\n%s", got, want)
+	}
+
+	out, buf = newOutput(output.Template)
+	out.Expression = "{{.name}} draft={{.draft}}"
+	if err := components.RenderStructs(out, releases); err != nil {
+		t.Fatalf("RenderStructs: %v", err)
+	}
+	if got := buf.String(); got != "v1 draft=true\nv2 draft=<no value>\n" {
+		t.Errorf("template got %q", got)
+	}
+}
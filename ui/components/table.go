@@ -1,42 +1,151 @@
 package components
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
 	"github.com/ikaitla/framework/ui/theme"
 )
 
 type Table struct {
-	headers []string
-	rows    [][]string
-	widths  []int
-	out     *output.Output
+	headers  []string
+	rows     []tableRow
+	widths   []int
+	columns  []Column
+	maxWidth int
+	out      *output.Output
+
+	border        BorderStyle
+	title         string
+	footer        []string
+	rowSeparators bool
+
+	sortCol int
+	sortCmp Compare
+}
+
+// tableRow keeps styles next to the cells so they survive sorting.
+type tableRow struct {
+	cells  []string
+	style  theme.Token
+	styles []theme.Token
 }
 
 func NewTable(out *output.Output, headers ...string) *Table {
 	widths := make([]int, len(headers))
 	for i, h := range headers {
-		widths[i] = len(h)
+		widths[i] = term.StringWidth(h)
 	}
 	return &Table{
 		out:     out,
 		headers: headers,
 		widths:  widths,
+		columns: make([]Column, len(headers)),
+		border:  BorderSimple,
+		sortCol: -1,
 	}
 }
 
-func (t *Table) AddRow(cells ...string) {
+// SetBorder picks the lines drawn in text mode. Styles using box-drawing
+// characters fall back to ASCII on terminals that cannot display them.
+func (t *Table) SetBorder(style BorderStyle) {
+	t.border = style
+}
+
+// SetTitle prints a centered title above the table in text mode.
+func (t *Table) SetTitle(title string) {
+	t.title = title
+}
+
+// SetFooter adds a summary row, such as totals, below the data rows in
+// text mode. Structured formats only carry the data rows.
+func (t *Table) SetFooter(cells ...string) {
+	t.footer = make([]string, len(t.headers))
+	for i := 0; i < len(t.headers) && i < len(cells); i++ {
+		t.footer[i] = cells[i]
+		if w := term.StringWidth(cells[i]); w > t.widths[i] {
+			t.widths[i] = w
+		}
+	}
+}
+
+// SetRowSeparators draws the style's row rule between data rows.
+func (t *Table) SetRowSeparators(on bool) {
+	t.rowSeparators = on
+}
+
+// SetColumn configures the layout of column i.
+func (t *Table) SetColumn(i int, c Column) {
+	if i >= 0 && i < len(t.columns) {
+		t.columns[i] = c
+	}
+}
+
+// SetMaxWidth fixes the total table width in text mode. By default (0) the
+// table fits the terminal and is not limited when output is redirected;
+// a negative width disables the limit.
+func (t *Table) SetMaxWidth(width int) {
+	t.maxWidth = width
+}
+
+// AddRow appends a row; style it through the returned Row.
+func (t *Table) AddRow(cells ...string) *Row {
 	row := make([]string, len(t.headers))
 	for i := 0; i < len(t.headers); i++ {
 		if i < len(cells) {
 			row[i] = cells[i]
-			if len(cells[i]) > t.widths[i] {
-				t.widths[i] = len(cells[i])
+			if w := term.StringWidth(cells[i]); w > t.widths[i] {
+				t.widths[i] = w
 			}
 		}
 	}
-	t.rows = append(t.rows, row)
+	t.rows = append(t.rows, tableRow{cells: row})
+	return &Row{table: t, index: len(t.rows) - 1}
+}
+
+// Len returns the number of rows added so far.
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Row is a row added to a Table. Its styles stay with it however SortBy
+// orders the rows.
+type Row struct {
+	table *Table
+	index int
+}
+
+// Style colors every cell of the row, e.g. theme.Danger600 for failures.
+func (r *Row) Style(token theme.Token) *Row {
+	r.table.rows[r.index].style = token
+	return r
+}
+
+// StyleCell colors one cell; it takes precedence over the row style.
+func (r *Row) StyleCell(col int, token theme.Token) *Row {
+	row := &r.table.rows[r.index]
+	if col < 0 || col >= len(row.cells) {
+		return r
+	}
+	if row.styles == nil {
+		row.styles = make([]theme.Token, len(row.cells))
+	}
+	row.styles[col] = token
+	return r
+}
+
+// SortBy orders the rows by column col when rendering. A nil cmp sorts
+// lexically; see CompareNumeric, CompareSemver and Descending.
+func (t *Table) SortBy(col int, cmp Compare) {
+	if col < 0 || col >= len(t.headers) {
+		return
+	}
+	if cmp == nil {
+		cmp = CompareText
+	}
+	t.sortCol, t.sortCmp = col, cmp
 }
 
 func (t *Table) Render() {
@@ -44,40 +153,320 @@ func (t *Table) Render() {
 		return
 	}
 
-	var headerLine strings.Builder
-	var separator strings.Builder
+	rows := t.sortedRows()
+
+	if t.out.Format != output.Text {
+		records := make([][]string, len(rows))
+		for i, r := range rows {
+			records[i] = r.cells
+		}
+		if err := t.out.PrintRecords(t.headers, records); err != nil {
+			t.out.Error("render table as %s: %v", t.out.Format, err)
+		}
+		return
+	}
+
+	if t.out.Filtering() {
+		t.renderFiltered(rows)
+		return
+	}
+	t.renderText(rows)
+}
+
+// renderFiltered applies --query and --fields in text mode. Fields alone
+// keep the column settings and styles; a query result is drawn as a plain
+// table while it is still rows of objects, and printed as a document
+// otherwise.
+func (t *Table) renderFiltered(rows []tableRow) {
+	if t.out.Query == "" {
+		sub, subRows := t.selectColumns(t.out.Fields, rows)
+		sub.renderText(subRows)
+		return
+	}
+
+	cells := make([][]string, len(rows))
+	for i, r := range rows {
+		cells[i] = make([]string, len(r.cells))
+		for j, c := range r.cells {
+			cells[i][j] = term.StripANSI(c)
+		}
+	}
+	v, err := t.out.Filter(output.Records(t.headers, cells))
+	if err != nil {
+		t.out.Error("filter table: %v", err)
+		return
+	}
+	if !objectRows(v) {
+		if err := t.out.Encode(v); err != nil {
+			t.out.Error("render query result: %v", err)
+		}
+		return
+	}
+
+	headers, records, err := output.Tabulate(v)
+	if err != nil {
+		t.out.Error("render query result as a table: %v", err)
+		return
+	}
+	sub := t.derive(headers)
+	for _, r := range records {
+		sub.AddRow(r...)
+	}
+	sub.renderText(sub.rows)
+}
+
+// selectColumns returns a table holding the named columns, matched
+// case-insensitively; unknown names become empty columns.
+func (t *Table) selectColumns(fields []string, rows []tableRow) (*Table, []tableRow) {
+	index := make([]int, len(fields))
+	headers := make([]string, len(fields))
+	for k, f := range fields {
+		index[k], headers[k] = -1, f
+		for i, h := range t.headers {
+			if h == f || (index[k] < 0 && strings.EqualFold(h, f)) {
+				index[k], headers[k] = i, h
+			}
+		}
+	}
+
+	sub := t.derive(headers)
+	for k, i := range index {
+		if i >= 0 {
+			sub.columns[k] = t.columns[i]
+		}
+	}
+	pick := func(values []string) []string {
+		out := make([]string, len(index))
+		for k, i := range index {
+			if i >= 0 && i < len(values) {
+				out[k] = values[i]
+			}
+		}
+		return out
+	}
+
+	for _, r := range rows {
+		sub.AddRow(pick(r.cells)...)
+		row := &sub.rows[len(sub.rows)-1]
+		row.style = r.style
+		if r.styles != nil {
+			row.styles = make([]theme.Token, len(index))
+			for k, i := range index {
+				if i >= 0 {
+					row.styles[k] = r.styles[i]
+				}
+			}
+		}
+	}
+	if t.footer != nil {
+		sub.SetFooter(pick(t.footer)...)
+	}
+	return sub, sub.rows
+}
+
+// derive creates an empty table with t's text settings and new headers.
+func (t *Table) derive(headers []string) *Table {
+	sub := NewTable(t.out, headers...)
+	sub.maxWidth = t.maxWidth
+	sub.border = t.border
+	sub.title = t.title
+	sub.rowSeparators = t.rowSeparators
+	return sub
+}
+
+// objectRows reports whether v is an object or a non-empty list of objects.
+func objectRows(v any) bool {
+	switch t := v.(type) {
+	case output.Object:
+		return true
+	case []any:
+		for _, item := range t {
+			if _, ok := item.(output.Object); !ok {
+				return false
+			}
+		}
+		return len(t) > 0
+	}
+	return false
+}
+
+func (t *Table) renderText(rows []tableRow) {
+	l := t.prepare()
+	l.begin(t)
+	for n, row := range rows {
+		l.row(t, row, n)
+	}
+	l.end(t)
+}
+
+// textLayout is the resolved style and geometry used to print a table,
+// shared by Table and StreamTable.
+type textLayout struct {
+	style   BorderStyle
+	visible []int
+	widths  []int
+	aligns  []Align
+}
+
+func (t *Table) prepare() textLayout {
+	style := t.border
+	if style.unicode() && term.IsTerminal(t.out.Out) && !term.SupportsUnicode() {
+		style = style.ascii()
+	}
+	natural := t.widths
+	if style.markdown() {
+		natural = t.escapedWidths()
+	}
+
+	visible, widths := t.layout(style, natural)
+	aligns := make([]Align, len(visible))
+	for k, i := range visible {
+		aligns[k] = t.alignment(i)
+	}
+	return textLayout{style: style, visible: visible, widths: widths, aligns: aligns}
+}
+
+// escape makes a cell safe for the style; only Markdown needs it.
+func (l textLayout) escape(cell string) string {
+	if l.style.markdown() {
+		return output.EscapeMarkdown(cell)
+	}
+	return cell
+}
+
+// escapedWidths returns the column widths grown to fit the escaped Markdown
+// cells, leaving the table's own widths alone for the next render.
+func (t *Table) escapedWidths() []int {
+	widths := append([]int(nil), t.widths...)
+	widen := func(cells []string) {
+		for i, c := range cells {
+			if w := term.StringWidth(output.EscapeMarkdown(c)); i < len(widths) && w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	widen(t.headers)
+	widen(t.footer)
+	for _, r := range t.rows {
+		widen(r.cells)
+	}
+	return widths
+}
+
+func (l textLayout) rule(t *Table, r Rule) {
+	if line, ok := l.style.rule(r, l.widths); ok {
+		t.out.Printf("%s", line)
+	}
+}
 
-	for i, h := range t.headers {
-		headerLine.WriteString(pad(h, t.widths[i]))
-		headerLine.WriteString("  ")
+// begin prints the title, top rule and header.
+func (l textLayout) begin(t *Table) {
+	if t.title != "" {
+		width := l.style.overhead(len(l.widths))
+		for _, w := range l.widths {
+			width += w
+		}
+		t.out.Printf("%s", t.out.Stylize(centered(t.title, width), theme.Slate900, theme.Bold))
+	}
+
+	l.rule(t, l.style.Top)
 
-		separator.WriteString(strings.Repeat("─", t.widths[i]))
-		separator.WriteString("  ")
+	header := make([]string, len(l.visible))
+	for k, i := range l.visible {
+		header[k] = t.out.Stylize(term.TruncateWidth(l.escape(t.headers[i]), l.widths[k], ellipsis), theme.Slate900, theme.Bold)
 	}
+	t.out.Printf("%s", l.style.line(header, l.widths, l.aligns))
+	l.rule(t, l.style.Header)
+}
 
-	hline := strings.TrimRight(headerLine.String(), " ")
-	sline := strings.TrimRight(separator.String(), " ")
+// row prints the n-th data row, over several lines when cells wrap.
+func (l textLayout) row(t *Table, row tableRow, n int) {
+	if n > 0 && t.rowSeparators {
+		l.rule(t, l.style.Row)
+	}
 
-	if t.out.ColorsEnabled() {
-		t.out.Printf("%s", t.out.Stylize(hline, theme.Slate900, theme.Bold))
-	} else {
-		t.out.Printf("%s", hline)
+	cells := make([][]string, len(l.visible))
+	height := 1
+	for k, i := range l.visible {
+		cells[k] = fitCell(l.escape(row.cells[i]), l.widths[k], t.columns[i].Overflow)
+		if len(cells[k]) > height {
+			height = len(cells[k])
+		}
 	}
-	t.out.Printf("%s", sline)
 
-	for _, row := range t.rows {
-		var rowLine strings.Builder
-		for i, cell := range row {
-			rowLine.WriteString(pad(cell, t.widths[i]))
-			rowLine.WriteString("  ")
+	for line := 0; line < height; line++ {
+		parts := make([]string, len(l.visible))
+		for k, i := range l.visible {
+			if line < len(cells[k]) {
+				parts[k] = cells[k][line]
+			}
+			if token := row.token(i); token != "" && parts[k] != "" {
+				parts[k] = t.out.Stylize(term.StripANSI(parts[k]), token)
+			}
 		}
-		t.out.Printf("%s", strings.TrimRight(rowLine.String(), " "))
+		t.out.Printf("%s", l.style.line(parts, l.widths, l.aligns))
+	}
+}
+
+// end prints the footer and bottom rule. Markdown allows a single
+// delimiter row, so its footer follows the data rows directly.
+func (l textLayout) end(t *Table) {
+	if t.footer != nil {
+		if !l.style.markdown() {
+			l.rule(t, l.style.Header)
+		}
+		l.row(t, tableRow{cells: t.footer}, 0)
+	}
+	l.rule(t, l.style.Bottom)
+}
+
+func (t *Table) sortedRows() []tableRow {
+	if t.sortCmp == nil {
+		return t.rows
+	}
+	rows := make([]tableRow, len(t.rows))
+	copy(rows, t.rows)
+	sort.SliceStable(rows, func(a, b int) bool {
+		return t.sortCmp(rows[a].cells[t.sortCol], rows[b].cells[t.sortCol]) < 0
+	})
+	return rows
+}
+
+// alignment resolves AlignAuto: numeric columns align right.
+func (t *Table) alignment(col int) Align {
+	if a := t.columns[col].Align; a != AlignAuto {
+		return a
+	}
+	numeric := false
+	for _, r := range t.rows {
+		cell := r.cells[col]
+		if strings.TrimSpace(term.StripANSI(cell)) == "" {
+			continue
+		}
+		if _, ok := parseNumber(cell); !ok {
+			return AlignLeft
+		}
+		numeric = true
+	}
+	if numeric {
+		return AlignRight
+	}
+	return AlignLeft
+}
+
+func (r tableRow) token(col int) theme.Token {
+	if r.styles != nil && r.styles[col] != "" {
+		return r.styles[col]
 	}
+	return r.style
 }
 
-func pad(s string, w int) string {
-	if len(s) >= w {
-		return s
+// joinCells aligns each cell in its column and trims the trailing space.
+func joinCells(cells []string, widths []int, aligns []Align) string {
+	var b strings.Builder
+	for k, cell := range cells {
+		b.WriteString(align(cell, widths[k], aligns[k]))
+		b.WriteString(strings.Repeat(" ", columnGap))
 	}
-	return s + strings.Repeat(" ", w-len(s))
+	return strings.TrimRight(b.String(), " ")
 }
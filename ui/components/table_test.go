@@ -0,0 +1,317 @@
+package components_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/components"
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+func newOutput(format output.Format) (*output.Output, *bytes.Buffer) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	out.Err = &buf
+	out.Format = format
+	out.ColorMode = term.ColorNever
+	return out, &buf
+}
+
+func TestTableRender_Text(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "Name", "Version")
+	table.AddRow("ikaitla", "0.1.0")
+	table.AddRow("ik", "1")
+	table.Render()
+
+	want := "Name     Version\n" +
+		"───────  ───────\n" +
+		"ikaitla  0.1.0\n" +
+		"ik       1\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableRender_JSON(t *testing.T) {
+	out, buf := newOutput(output.JSON)
+	table := components.NewTable(out, "Name", "Version")
+	table.AddRow("ikaitla", "0.1.0")
+	table.Render()
+
+	want := "[\n  {\n    \"Name\": \"ikaitla\",\n    \"Version\": \"0.1.0\"\n  }\n]\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenderKeyValue_YAML(t *testing.T) {
+	out, buf := newOutput(output.YAML)
+	components.RenderKeyValue(out, map[string]string{"version": "1.0", "name": "ikaitla"})
+
+	want := "name: ikaitla\nversion: \"1.0\"\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableRender_WideAndStyledCells(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "Name", "Status")
+	table.AddRow("日本", "\x1b[32mok\x1b[0m")
+	table.AddRow("café", "failed")
+	table.Render()
+
+	want := "Name  Status\n" +
+		"────  ──────\n" +
+		"日本  \x1b[32mok\x1b[0m\n" +
+		"café  failed\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
+
+func TestTableLayout_TruncateWrapAndDrop(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "ID", "Description", "Owner", "Notes")
+	table.SetMaxWidth(30)
+	table.SetColumn(1, components.Column{Overflow: components.Wrap})
+	table.SetColumn(2, components.Column{MaxWidth: 5})
+	table.SetColumn(3, components.Column{Priority: 1})
+	table.AddRow("1", "deploy the new release", "platform-team", "dropped first")
+	table.Render()
+
+	want := "ID  Description          Owner\n" +
+		"──  ───────────────────  ─────\n" +
+		" 1  deploy the new       plat…\n" +
+		"    release\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableSortAlignAndStyle(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	out.ColorMode = term.ColorAlways
+	table := components.NewTable(out, "Version", "Size", "State")
+	table.SetColumn(2, components.Column{Align: components.AlignCenter})
+	table.AddRow("v1.10.0", "2048", "ok")
+	table.AddRow("v1.9.2", "512", "failed").Style(theme.Danger600)
+	table.AddRow("v1.10.0-rc.1", "1,024", "ok")
+	table.SortBy(0, components.Descending(components.CompareSemver))
+	table.Render()
+
+	red := theme.ResolveANSI(theme.Danger600)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"v1.10.0        2048    ok",
+		"v1.10.0-rc.1  1,024    ok",
+		red + "v1.9.2" + theme.Reset + "          " + red + "512" + theme.Reset + "  " + red + "failed" + theme.Reset,
+	}
+	if len(lines) != 5 {
+		t.Fatalf("expected 5 lines, got %q", lines)
+	}
+	for i, w := range want {
+		if lines[i+2] != w {
+			t.Errorf("line %d:\n got %q\nwant %q", i+2, lines[i+2], w)
+		}
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	ordered := []string{"0.9.0", "1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0", "v1.2", "2.0.0", "latest"}
+	for i := 0; i+1 < len(ordered); i++ {
+		if components.CompareSemver(ordered[i], ordered[i+1]) >= 0 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestCompareNumeric(t *testing.T) {
+	// inf, nan and hex are text, so they sort after numbers, lexically
+	ordered := []string{"-3", "0.5", "40%", "1,024", "1e4", "0x10", "Inf", "NaN", "inf"}
+	for i := 0; i+1 < len(ordered); i++ {
+		if components.CompareNumeric(ordered[i], ordered[i+1]) >= 0 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+}
+
+func TestTableBorders(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "Service", "Errors")
+	table.SetBorder(components.BorderRounded)
+	table.SetTitle("Health")
+	table.SetRowSeparators(true)
+	table.AddRow("api", "3")
+	table.AddRow("worker", "12")
+	table.SetFooter("total", "15")
+	table.Render()
+
+	want := "       Health\n" +
+		"╭─────────┬────────╮\n" +
+		"│ Service │ Errors │\n" +
+		"├─────────┼────────┤\n" +
+		"│ api     │      3 │\n" +
+		"├─────────┼────────┤\n" +
+		"│ worker  │     12 │\n" +
+		"├─────────┼────────┤\n" +
+		"│ total   │     15 │\n" +
+		"╰─────────┴────────╯\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableBorders_Markdown(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "Name", "Count")
+	table.SetBorder(components.BorderMarkdown)
+	table.AddRow("a", "1")
+	table.AddRow("a|b", "2")
+	table.Render()
+
+	want := "| Name | Count |\n" +
+		"|------|-------|\n" +
+		"| a    |     1 |\n" +
+		"| a\\|b |     2 |\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableBorders_MarkdownFooterRendersTwice(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewTable(out, "Name", "Count")
+	table.SetBorder(components.BorderMarkdown)
+	table.AddRow("a|b|c", "1")
+	table.SetFooter("total", "1")
+
+	want := "| Name    | Count |\n" +
+		"|---------|-------|\n" +
+		"| a\\|b\\|c |     1 |\n" +
+		"| total   |     1 |\n"
+	for i := 0; i < 2; i++ {
+		buf.Reset()
+		table.Render()
+		if got := buf.String(); got != want {
+			t.Fatalf("render %d: got:\n%s\nwant:\n%s", i+1, got, want)
+		}
+	}
+
+	// escaping must not leave the other styles with the wider columns
+	buf.Reset()
+	table.SetBorder(components.BorderNone)
+	table.Render()
+	if got, want := strings.SplitN(buf.String(), "\n", 2)[0], "Name   Count"; got != want {
+		t.Fatalf("plain header after Markdown = %q, want %q", got, want)
+	}
+}
+
+func TestStreamTable_Text(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	table := components.NewStreamTable(out, "Name", "Note")
+	table.SetSampleSize(2)
+
+	table.AddRow("a", "short")
+	if buf.Len() != 0 {
+		t.Fatalf("expected rows to be buffered during sampling, got %q", buf.String())
+	}
+	table.AddRow("bb", "longer")
+	if !strings.Contains(buf.String(), "bb    longer") {
+		t.Fatalf("expected sample to be flushed, got %q", buf.String())
+	}
+
+	table.AddRow("ccc", "much longer than the sample")
+	table.Close()
+
+	want := "Name  Note\n" +
+		"────  ──────\n" +
+		"a     short\n" +
+		"bb    longer\n" +
+		"ccc   much…\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestStreamTable_JSONLines(t *testing.T) {
+	out, buf := newOutput(output.JSON)
+	table := components.NewStreamTable(out, "Name", "Count")
+	table.AddRow("a", "1")
+	if got := buf.String(); got != "{\"Name\":\"a\",\"Count\":\"1\"}\n" {
+		t.Fatalf("expected row to be written immediately, got %q", got)
+	}
+	table.AddRow("b", "2")
+	table.Close()
+
+	want := "{\"Name\":\"a\",\"Count\":\"1\"}\n{\"Name\":\"b\",\"Count\":\"2\"}\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableRender_QueryAndFields(t *testing.T) {
+	out, buf := newOutput(output.Text)
+	out.Fields = []string{"state", "name"}
+	table := components.NewTable(out, "Name", "State", "Size")
+	table.AddRow("api", "running", "120")
+	table.AddRow("db", "failed", "4096")
+	table.Render()
+
+	want := "State    Name\n" +
+		"───────  ────\n" +
+		"running  api\n" +
+		"failed   db\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("fields: got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	out.Fields = nil
+	out.Query = "[?State == 'failed'].{Name: Name, Size: Size}"
+	table.Render()
+	want = "Name  Size\n" +
+		"────  ────\n" +
+		"db    4096\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("query: got:\n%s\nwant:\n%s", got, want)
+	}
+
+	buf.Reset()
+	out.Query = "length(@)"
+	table.Render()
+	if got := buf.String(); got != "2\n" {
+		t.Fatalf("scalar query: got %q", got)
+	}
+}
+
+func TestTableRender_DuplicateHeaders(t *testing.T) {
+	out, buf := newOutput(output.JSON)
+	table := components.NewTable(out, "Name", "Size", "Name")
+	table.AddRow("api", "1", "web")
+	table.Render()
+
+	want := "[\n  {\n    \"Name\": \"api\",\n    \"Size\": \"1\",\n    \"Name_2\": \"web\"\n  }\n]\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTableRender_ReportsEncodeErrors(t *testing.T) {
+	out, buf := newOutput(output.CSV)
+	if err := out.SetQuery("[[Name]]"); err != nil {
+		t.Fatal(err)
+	}
+	table := components.NewTable(out, "Name")
+	table.AddRow("api")
+	table.Render()
+
+	if got := buf.String(); !strings.HasPrefix(got, "[✗] render table as csv: ") {
+		t.Fatalf("got %q", got)
+	}
+}
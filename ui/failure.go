@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/ikaitla/framework/ui/output"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+// Failure is the user-facing description of an error that ends a command.
+type Failure struct {
+	Message  string `json:"message"`
+	Category string `json:"category,omitempty"`
+	Code     int    `json:"code"`
+	Hint     string `json:"hint,omitempty"`
+	DocsURL  string `json:"docs,omitempty"`
+}
+
+// Fail reports f on stderr: through Error, followed by the hint and docs
+// link, in text and tabular modes, and as an {"error": {...}} document in
+// the others. YAML gets YAML; JSON, template and JSONPath get JSON, since
+// an expression is written for the command's data and not for errors.
+func Fail(f Failure) {
+	if format := defaultOut.Format; format != output.Text && !format.Tabular() {
+		var buf bytes.Buffer
+		enc := &output.Output{Out: &buf, Format: output.JSON}
+		if format == output.YAML {
+			enc.Format = output.YAML
+		}
+		if err := enc.Encode(output.Object{{Key: "error", Value: f}}); err == nil {
+			defaultOut.Errorf("%s", strings.TrimSuffix(buf.String(), "\n"))
+			return
+		}
+	}
+
+	Error("%s", f.Message)
+	if f.Hint != "" {
+		defaultOut.Errorf("    %s %s", defaultOut.Stylize("hint:", theme.Slate500), f.Hint)
+	}
+	if f.DocsURL != "" {
+		defaultOut.Errorf("    %s %s", defaultOut.Stylize("docs:", theme.Slate500), defaultOut.Stylize(f.DocsURL, theme.Info600))
+	}
+}
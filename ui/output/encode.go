@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Print encodes v in the selected Format. Values go through encoding/json
+// first, so `json` struct tags and MarshalJSON apply to every format.
+//
+// --query and --fields are applied first; see Filter.
+//
+// Tabular formats accept objects or arrays of objects, one row each.
+// Template and JSONPath see v as generic JSON; see printExpression.
+// Text prints strings, errors and fmt.Stringers as-is and falls back to YAML
+// for anything else, which stays readable for nested data.
+func (o *Output) Print(v any) error {
+	v, err := o.Filter(v)
+	if err != nil {
+		return err
+	}
+	return o.Encode(v)
+}
+
+// Encode is Print without --query and --fields, for values that were
+// already filtered.
+func (o *Output) Encode(v any) error {
+	switch o.Format {
+	case JSON:
+		return o.PrintJSON(v)
+	case YAML:
+		return o.PrintYAML(v)
+	case Template, JSONPath:
+		return o.printExpression(v)
+	}
+	if o.Format.Tabular() {
+		headers, rows, err := Tabulate(v)
+		if err != nil {
+			return fmt.Errorf("output format %s: %w", o.Format, err)
+		}
+		return o.writeRecords(headers, rows)
+	}
+
+	switch t := v.(type) {
+	case string:
+		o.Printf("%s", t)
+		return nil
+	case error:
+		o.Printf("%s", t.Error())
+		return nil
+	case fmt.Stringer:
+		o.Printf("%s", t.String())
+		return nil
+	}
+	return o.PrintYAML(v)
+}
+
+// PrintYAML writes v as a YAML document. Object keys keep the order produced
+// by encoding/json: struct fields in declaration order, map keys sorted.
+func (o *Output) PrintYAML(v any) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return encodeYAML(o.Out, v)
+}
+
+func encodeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML, and decoding into a Node keeps key order.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	blockStyle(&doc)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// blockStyle drops the flow/quoted styles inherited from JSON so the encoder
+// picks idiomatic block YAML, quoting only where a plain scalar would change type.
+func blockStyle(n *yaml.Node) {
+	n.Style = 0
+	for _, c := range n.Content {
+		blockStyle(c)
+	}
+}
@@ -0,0 +1,104 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+func TestPrintYAML(t *testing.T) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	out.Format = output.YAML
+
+	v := struct {
+		Name    string            `json:"name"`
+		Version string            `json:"version"`
+		Aliases []string          `json:"aliases"`
+		Labels  map[string]string `json:"labels"`
+		Empty   string            `json:"empty,omitempty"`
+	}{
+		Name:    "ikaitla",
+		Version: "1.0",
+		Aliases: []string{"ik"},
+		Labels:  map[string]string{"zone": "eu", "tier": "gold"},
+	}
+
+	if err := out.Print(v); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	want := `name: ikaitla
+version: "1.0"
+aliases:
+  - ik
+labels:
+  tier: gold
+  zone: eu
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := output.ParseFormat("YAML"); err != nil || f != output.YAML {
+		t.Fatalf("ParseFormat(YAML) = %q, %v", f, err)
+	}
+	if _, err := output.ParseFormat("xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestPrintTemplateAndJSONPath(t *testing.T) {
+	type item struct {
+		Name    string   `json:"name"`
+		Size    int64    `json:"size"`
+		Tags    []string `json:"tags"`
+		Healthy bool     `json:"healthy"`
+	}
+	items := []item{
+		{Name: "api", Size: 1536, Tags: []string{"web", "public"}, Healthy: true},
+		{Name: "db", Size: 2 << 20, Tags: []string{"internal"}},
+	}
+
+	tests := []struct {
+		spec string
+		v    any
+		want string
+	}{
+		{`template={{.name}}: {{.size | bytes}} [{{join "," .tags}}]`, items,
+			"api: 1.5 KiB [web,public]\ndb: 2.0 MiB [internal]\n"},
+		{`template={{pad 5 .name}}|`, items[0], "api  |\n"},
+		{`jsonpath={.items[*].name}`, map[string]any{"items": items}, "api db\n"},
+		{`jsonpath={range [*]}{.name}{"\t"}{.size}{"\n"}{end}`, items, "api\t1536\ndb\t2097152\n"},
+		{`jsonpath={[?(@.healthy)].name}`, items, "api\n"},
+		{`jsonpath={[?(@.size > 2000)].name}`, items, "db\n"},
+		{`jsonpath={[-1].tags[0]}`, items, "internal\n"},
+		{`jsonpath={..tags[1]}`, items, "public\n"},
+		{`jsonpath={[?(@.name != "a==b")].name}`, items, "api db\n"},
+		{`jsonpath={[?(@.name < 'b<c')].name}`, items, "api\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		out := output.New()
+		out.Out = &buf
+		if err := out.SetFormatSpec(tt.spec); err != nil {
+			t.Fatalf("SetFormatSpec(%s): %v", tt.spec, err)
+		}
+		if err := out.Print(tt.v); err != nil {
+			t.Fatalf("Print(%s): %v", tt.spec, err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Fatalf("%s: got %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"template", "template={{.name", "jsonpath={range .x}", "jsonpath={range}{.x}{end}", "json=.x"} {
+		if err := output.New().SetFormatSpec(spec); err == nil {
+			t.Fatalf("SetFormatSpec(%s): expected error", spec)
+		}
+	}
+}
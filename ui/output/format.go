@@ -1,8 +1,85 @@
 package output
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Format string
 
 const (
 	Text Format = "text"
 	JSON Format = "json"
+	YAML Format = "yaml"
+
+	CSV      Format = "csv"
+	TSV      Format = "tsv"
+	Markdown Format = "markdown"
+	HTML     Format = "html"
+
+	// Template and JSONPath take an expression: -o template='{{.name}}',
+	// -o jsonpath='{.items[*].name}'. See Output.SetFormatSpec.
+	Template Format = "template"
+	JSONPath Format = "jsonpath"
 )
+
+// formatAliases are accepted by ParseFormat but not advertised.
+var formatAliases = map[string]Format{
+	"md": Markdown,
+}
+
+// Formats lists every supported format, in the order shown to users.
+func Formats() []Format {
+	return []Format{Text, JSON, YAML, CSV, TSV, Markdown, HTML, Template, JSONPath}
+}
+
+// exprPlaceholders name the argument of formats that need one, for help text.
+var exprPlaceholders = map[Format]string{
+	Template: "TEMPLATE",
+	JSONPath: "EXPR",
+}
+
+// FormatNames returns Formats as plain strings, e.g. for flag help.
+func FormatNames() []string {
+	formats := Formats()
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = string(f)
+		if p, ok := exprPlaceholders[f]; ok {
+			names[i] += "=" + p
+		}
+	}
+	return names
+}
+
+// ParseFormat validates a user-supplied format name.
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats() {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	if f, ok := formatAliases[strings.ToLower(s)]; ok {
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown output format %q (expected %s)", s, strings.Join(FormatNames(), "|"))
+}
+
+// ParseFormatSpec splits a --output value such as "json" or
+// "template={{.name}}" into the format and its expression. Template and
+// JSONPath require an expression; other formats reject one.
+func ParseFormatSpec(spec string) (Format, string, error) {
+	name, expr, hasExpr := strings.Cut(spec, "=")
+	f, err := ParseFormat(name)
+	if err != nil {
+		return "", "", err
+	}
+	_, needsExpr := exprPlaceholders[f]
+	switch {
+	case needsExpr && expr == "":
+		return "", "", fmt.Errorf("output format %s requires an expression (%s=%s)", f, f, exprPlaceholders[f])
+	case !needsExpr && hasExpr:
+		return "", "", fmt.Errorf("output format %s does not take an expression", f)
+	}
+	return f, expr, nil
+}
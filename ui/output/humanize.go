@@ -0,0 +1,34 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// HumanBytes formats a byte count with binary units, e.g. "1.5 KiB".
+func HumanBytes(n int64) string {
+	const unit = 1024
+	if n < unit && n > -unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value := float64(n)
+	suffixes := []string{"KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+	i := -1
+	for (value >= unit || value <= -unit) && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
+// HumanDuration rounds d to a precision that reads well: milliseconds
+// below a second, tenths of a second below a minute, seconds above.
+func HumanDuration(d time.Duration) string {
+	switch abs := d.Abs(); {
+	case abs < time.Second:
+		return d.Round(time.Millisecond).String()
+	case abs < time.Minute:
+		return d.Round(100 * time.Millisecond).String()
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,573 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONPathExpr is a compiled kubectl-style JSONPath template such as
+// `{.items[*].name}` or `{range .items[*]}{.name}{"\n"}{end}`.
+//
+// Supported: child (.a, ['a']), wildcard ([*], .*), index ([0], [-1]),
+// slice ([1:3]), recursive descent (..name), filters
+// ([?(@.state == "failed")] with == != < <= > >= or bare @.field),
+// quoted literals ({"\t"}) and range/end blocks. Several results of one
+// expression are separated by a space.
+type JSONPathExpr struct {
+	nodes []jpNode
+}
+
+type jpNode struct {
+	text  string   // literal text when path is nil and body is nil
+	path  []jpStep // expression to print
+	rng   bool     // range block over path
+	body  []jpNode // nodes of a range block
+	isLit bool
+}
+
+type jpStepKind int
+
+const (
+	jpField jpStepKind = iota
+	jpWildcard
+	jpIndex
+	jpSlice
+	jpRecursive
+	jpFilterStep
+)
+
+type jpStep struct {
+	kind       jpStepKind
+	name       string
+	index      int
+	start, end *int
+	filter     *jpFilter
+}
+
+type jpFilter struct {
+	left  []jpStep
+	op    string
+	right any
+}
+
+// ParseJSONPath compiles expr. An expression without braces is treated as
+// a single path, so `.items[*].name` equals `{.items[*].name}`.
+func ParseJSONPath(expr string) (*JSONPathExpr, error) {
+	if !strings.Contains(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+
+	var stack [][]jpNode
+	var current []jpNode
+	var ranges []jpNode
+
+	for len(expr) > 0 {
+		open := strings.IndexByte(expr, '{')
+		if open < 0 {
+			current = append(current, jpNode{text: expr, isLit: true})
+			break
+		}
+		if open > 0 {
+			current = append(current, jpNode{text: expr[:open], isLit: true})
+		}
+		close, err := matchBrace(expr, open)
+		if err != nil {
+			return nil, err
+		}
+		inner := strings.TrimSpace(expr[open+1 : close])
+		expr = expr[close+1:]
+
+		switch {
+		case inner == "end":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("jsonpath: {end} without {range}")
+			}
+			rng := ranges[len(ranges)-1]
+			rng.body = current
+			ranges = ranges[:len(ranges)-1]
+			current = append(stack[len(stack)-1], rng)
+			stack = stack[:len(stack)-1]
+		case inner == "range" || strings.HasPrefix(inner, "range "):
+			arg := strings.TrimSpace(strings.TrimPrefix(inner, "range"))
+			if arg == "" {
+				return nil, fmt.Errorf("jsonpath: {range} needs a path, e.g. {range .items[*]}")
+			}
+			path, err := parsePath(arg)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, current)
+			ranges = append(ranges, jpNode{rng: true, path: path})
+			current = nil
+		case strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'"):
+			lit, err := unquote(inner)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: literal %s: %w", inner, err)
+			}
+			current = append(current, jpNode{text: lit, isLit: true})
+		default:
+			path, err := parsePath(inner)
+			if err != nil {
+				return nil, err
+			}
+			current = append(current, jpNode{path: path})
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("jsonpath: {range} without {end}")
+	}
+	return &JSONPathExpr{nodes: current}, nil
+}
+
+// Execute writes the template applied to data, which should be generic JSON
+// (maps, slices, strings, json.Number, bools and nil).
+func (p *JSONPathExpr) Execute(w io.Writer, data any) error {
+	var buf bytes.Buffer
+	if err := execNodes(&buf, p.nodes, data); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func execNodes(buf *bytes.Buffer, nodes []jpNode, data any) error {
+	for _, n := range nodes {
+		switch {
+		case n.isLit:
+			buf.WriteString(n.text)
+		case n.rng:
+			for _, item := range evalPath(n.path, data) {
+				if err := execNodes(buf, n.body, item); err != nil {
+					return err
+				}
+			}
+		default:
+			results := evalPath(n.path, data)
+			for i, r := range results {
+				if i > 0 {
+					buf.WriteByte(' ')
+				}
+				s, err := jsonText(r)
+				if err != nil {
+					return err
+				}
+				buf.WriteString(s)
+			}
+		}
+	}
+	return nil
+}
+
+// jsonText prints scalars plainly and anything else as compact JSON.
+func jsonText(v any) (string, error) {
+	switch t := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return t, nil
+	case json.Number:
+		return t.String(), nil
+	case bool:
+		return strconv.FormatBool(t), nil
+	}
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func matchBrace(s string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("jsonpath: unclosed { in %q", s[open:])
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated string")
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+// parsePath compiles a path such as `$.items[?(@.ok)].name`.
+func parsePath(s string) ([]jpStep, error) {
+	orig := s
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "$"), "@")
+
+	var steps []jpStep
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			s = s[2:]
+			steps = append(steps, jpStep{kind: jpRecursive})
+			if strings.HasPrefix(s, "[") {
+				continue
+			}
+			name, rest := splitName(s)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: missing name after .. in %q", orig)
+			}
+			steps = append(steps, fieldStep(name))
+			s = rest
+		case s[0] == '.':
+			name, rest := splitName(s[1:])
+			s = rest
+			if name == "" {
+				continue // "." alone is the current node
+			}
+			steps = append(steps, fieldStep(name))
+		case s[0] == '[':
+			end, err := matchBracket(s)
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %w in %q", err, orig)
+			}
+			step, err := parseBracket(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath: %w in %q", err, orig)
+			}
+			steps = append(steps, step)
+			s = s[end+1:]
+		default:
+			name, rest := splitName(s)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath: unexpected %q in %q", s, orig)
+			}
+			steps = append(steps, fieldStep(name))
+			s = rest
+		}
+	}
+	return steps, nil
+}
+
+func fieldStep(name string) jpStep {
+	if name == "*" {
+		return jpStep{kind: jpWildcard}
+	}
+	return jpStep{kind: jpField, name: name}
+}
+
+func splitName(s string) (string, string) {
+	i := strings.IndexAny(s, ".[")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+func matchBracket(s string) (int, error) {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed [")
+}
+
+func parseBracket(s string) (jpStep, error) {
+	switch {
+	case s == "*":
+		return jpStep{kind: jpWildcard}, nil
+	case strings.HasPrefix(s, "?(") && strings.HasSuffix(s, ")"):
+		f, err := parseFilter(strings.TrimSpace(s[2 : len(s)-1]))
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: jpFilterStep, filter: f}, nil
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		name, err := unquote(s)
+		if err != nil {
+			return jpStep{}, err
+		}
+		return jpStep{kind: jpField, name: name}, nil
+	case strings.Contains(s, ":"):
+		parts := strings.SplitN(s, ":", 2)
+		step := jpStep{kind: jpSlice}
+		for i, p := range parts {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return jpStep{}, fmt.Errorf("invalid slice [%s]", s)
+			}
+			if i == 0 {
+				step.start = &n
+			} else {
+				step.end = &n
+			}
+		}
+		return step, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return jpStep{}, fmt.Errorf("invalid index [%s]", s)
+	}
+	return jpStep{kind: jpIndex, index: n}, nil
+}
+
+var filterOps = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+func parseFilter(s string) (*jpFilter, error) {
+	if !strings.HasPrefix(s, "@") {
+		return nil, fmt.Errorf("filter must start with @: %q", s)
+	}
+	if i, op := findOperator(s); op != "" {
+		left, err := parsePath(strings.TrimSpace(s[:i]))
+		if err != nil {
+			return nil, err
+		}
+		right, err := parseLiteral(strings.TrimSpace(s[i+len(op):]))
+		if err != nil {
+			return nil, err
+		}
+		return &jpFilter{left: left, op: op, right: right}, nil
+	}
+	left, err := parsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	return &jpFilter{left: left}, nil
+}
+
+// findOperator returns the first comparison operator of s that is not
+// inside a quoted literal, and its index.
+func findOperator(s string) (int, string) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		default:
+			for _, op := range filterOps {
+				if strings.HasPrefix(s[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+func parseLiteral(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "'") || strings.HasPrefix(s, `"`):
+		return unquote(s)
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err != nil {
+		return nil, fmt.Errorf("invalid literal %q", s)
+	}
+	return json.Number(s), nil
+}
+
+func evalPath(steps []jpStep, data any) []any {
+	current := []any{data}
+	for _, step := range steps {
+		var next []any
+		for _, v := range current {
+			next = append(next, evalStep(step, v)...)
+		}
+		current = next
+	}
+	return current
+}
+
+func evalStep(step jpStep, v any) []any {
+	switch step.kind {
+	case jpField:
+		if m, ok := v.(map[string]any); ok {
+			if child, ok := m[step.name]; ok {
+				return []any{child}
+			}
+		}
+	case jpWildcard:
+		return children(v)
+	case jpIndex:
+		if list, ok := v.([]any); ok {
+			i := step.index
+			if i < 0 {
+				i += len(list)
+			}
+			if i >= 0 && i < len(list) {
+				return []any{list[i]}
+			}
+		}
+	case jpSlice:
+		if list, ok := v.([]any); ok {
+			start, end := 0, len(list)
+			if step.start != nil {
+				start = clampIndex(*step.start, len(list))
+			}
+			if step.end != nil {
+				end = clampIndex(*step.end, len(list))
+			}
+			if start < end {
+				return list[start:end]
+			}
+		}
+	case jpRecursive:
+		return descendants(v)
+	case jpFilterStep:
+		var out []any
+		for _, child := range children(v) {
+			if step.filter.match(child) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	return max(0, min(i, n))
+}
+
+// children lists array elements, or map values sorted by key.
+func children(v any) []any {
+	switch t := v.(type) {
+	case []any:
+		return t
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]any, len(keys))
+		for i, k := range keys {
+			out[i] = t[k]
+		}
+		return out
+	}
+	return nil
+}
+
+// descendants returns v and everything below it, depth first.
+func descendants(v any) []any {
+	out := []any{v}
+	for _, child := range children(v) {
+		out = append(out, descendants(child)...)
+	}
+	return out
+}
+
+func (f *jpFilter) match(v any) bool {
+	results := evalPath(f.left, v)
+	if f.op == "" {
+		for _, r := range results {
+			if r != nil && r != false {
+				return true
+			}
+		}
+		return false
+	}
+	for _, r := range results {
+		if compareLiteral(r, f.op, f.right) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareLiteral(left any, op string, right any) bool {
+	if ln, ok := left.(json.Number); ok {
+		if rn, ok := right.(json.Number); ok {
+			a, errA := ln.Float64()
+			b, errB := rn.Float64()
+			if errA == nil && errB == nil {
+				return compareOrdered(a, b, op)
+			}
+		}
+	}
+	if ls, ok := left.(string); ok {
+		if rs, ok := right.(string); ok {
+			return compareOrdered(ls, rs, op)
+		}
+	}
+	switch op {
+	case "==":
+		return left == right
+	case "!=":
+		return left != right
+	}
+	return false
+}
+
+func compareOrdered[T float64 | string](a, b T, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
@@ -0,0 +1,47 @@
+package output
+
+import "sync"
+
+// Live is output redrawn in place, such as a spinner or progress bar.
+// Clear stops its updates and erases it.
+type Live interface {
+	Clear()
+}
+
+// liveSet tracks the Live elements currently on screen.
+type liveSet struct {
+	mu    sync.Mutex
+	items map[Live]struct{}
+}
+
+// AddLive registers l until RemoveLive, so ClearLive can erase it.
+func (o *Output) AddLive(l Live) {
+	o.live.mu.Lock()
+	defer o.live.mu.Unlock()
+	if o.live.items == nil {
+		o.live.items = map[Live]struct{}{}
+	}
+	o.live.items[l] = struct{}{}
+}
+
+// RemoveLive unregisters l.
+func (o *Output) RemoveLive(l Live) {
+	o.live.mu.Lock()
+	defer o.live.mu.Unlock()
+	delete(o.live.items, l)
+}
+
+// ClearLive erases every registered Live element, e.g. on Ctrl-C before
+// printing a message, so no half-drawn line is left behind.
+func (o *Output) ClearLive() {
+	o.live.mu.Lock()
+	items := make([]Live, 0, len(o.live.items))
+	for l := range o.live.items {
+		items = append(items, l)
+	}
+	o.live.mu.Unlock()
+
+	for _, l := range items {
+		l.Clear()
+	}
+}
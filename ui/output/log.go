@@ -0,0 +1,341 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+// Log levels. They are slog levels, with Trace added below Debug, so the
+// logger and slog handlers share one scale.
+const (
+	LevelTrace = slog.Level(-8)
+	LevelDebug = slog.LevelDebug
+	LevelInfo  = slog.LevelInfo
+	LevelWarn  = slog.LevelWarn
+	LevelError = slog.LevelError
+)
+
+// LevelNames lists the names accepted by ParseLevel, e.g. for flag help.
+func LevelNames() []string {
+	return []string{"trace", "debug", "info", "warn", "error"}
+}
+
+// ParseLevel validates a user-supplied level name.
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(s) {
+	case "trace":
+		return LevelTrace, nil
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level %q (expected %s)", s, strings.Join(LevelNames(), "|"))
+}
+
+// VerbosityLevel maps a --verbose count to a level: info by default,
+// debug with -v and trace with -vv.
+func VerbosityLevel(verbosity int) slog.Level {
+	switch {
+	case verbosity >= 2:
+		return LevelTrace
+	case verbosity == 1:
+		return LevelDebug
+	}
+	return LevelInfo
+}
+
+// LevelName returns the short upper-case name of a level.
+func LevelName(l slog.Level) string {
+	switch {
+	case l < LevelDebug:
+		return "TRACE"
+	case l < LevelInfo:
+		return "DEBUG"
+	case l < LevelWarn:
+		return "INFO"
+	case l < LevelError:
+		return "WARN"
+	}
+	return "ERROR"
+}
+
+// LogEnabled reports whether a message at level would be written. Logs go
+// to Err and are dropped below LogLevel, and in JSON mode when Err ends up
+// on stdout, where they would corrupt the document.
+func (o *Output) LogEnabled(level slog.Level) bool {
+	return level >= o.LogLevel && !o.logOnStdout()
+}
+
+func (o *Output) logOnStdout() bool {
+	if o.Format != JSON {
+		return false
+	}
+	if o.Err == o.Out {
+		return true
+	}
+	errFile, ok := o.Err.(*os.File)
+	if !ok {
+		return false
+	}
+	outFile, ok := o.Out.(*os.File)
+	if !ok || term.IsTerminal(outFile) {
+		return false
+	}
+	a, errA := errFile.Stat()
+	b, errB := outFile.Stat()
+	return errA == nil && errB == nil && os.SameFile(a, b)
+}
+
+// Logger is a leveled logger writing to Output.Err. Messages are formatted
+// like the ui helpers; key/value attributes are added with With.
+type Logger struct {
+	handler slog.Handler
+}
+
+// Logger returns a logger bound to o.
+func (o *Output) Logger() *Logger {
+	return &Logger{handler: o.LogHandler()}
+}
+
+// With returns a logger that adds the given key/value pairs to every line.
+func (l *Logger) With(args ...any) *Logger {
+	r := slog.NewRecord(time.Time{}, 0, "", 0)
+	r.Add(args...)
+	var attrs []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	return &Logger{handler: l.handler.WithAttrs(attrs)}
+}
+
+// Handler exposes the logger as a slog.Handler.
+func (l *Logger) Handler() slog.Handler { return l.handler }
+
+// Slog returns a *slog.Logger sharing this logger's output and level.
+func (l *Logger) Slog() *slog.Logger { return slog.New(l.handler) }
+
+func (l *Logger) Trace(format string, args ...any) { l.log(LevelTrace, format, args...) }
+func (l *Logger) Debug(format string, args ...any) { l.log(LevelDebug, format, args...) }
+func (l *Logger) Info(format string, args ...any)  { l.log(LevelInfo, format, args...) }
+func (l *Logger) Warn(format string, args ...any)  { l.log(LevelWarn, format, args...) }
+func (l *Logger) Error(format string, args ...any) { l.log(LevelError, format, args...) }
+
+func (l *Logger) log(level slog.Level, format string, args ...any) {
+	ctx := context.Background()
+	if !l.handler.Enabled(ctx, level) {
+		return
+	}
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	r := slog.NewRecord(time.Now(), level, fmt.Sprintf(format, args...), pcs[0])
+	_ = l.handler.Handle(ctx, r)
+}
+
+// LogHandler returns a slog.Handler that writes to o.Err at o.LogLevel,
+// so slog.New(out.LogHandler()) logs like the ui logger.
+func (o *Output) LogHandler() slog.Handler {
+	return &logHandler{out: o}
+}
+
+type logHandler struct {
+	out    *Output
+	attrs  []slog.Attr
+	groups []string
+}
+
+func (h *logHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.out.LogEnabled(level)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(append([]slog.Attr(nil), h.attrs...), h.qualify(attrs)...)
+	return &c
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.groups = append(append([]string(nil), h.groups...), name)
+	return &c
+}
+
+// Handle writes one record. Text puts the themed level and message on one
+// line and the attributes below, keys aligned as in RenderKeyValue; JSON
+// output writes one object per line instead.
+func (h *logHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := append([]slog.Attr(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.qualify([]slog.Attr{a})...)
+		return true
+	})
+
+	var line []byte
+	if h.out.Format == JSON {
+		data, err := json.Marshal(logObject(r, attrs))
+		if err != nil {
+			return err
+		}
+		line = append(data, '\n')
+	} else {
+		line = []byte(h.text(r, flattenAttrs("", attrs)))
+	}
+
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	_, err := h.out.Err.Write(line)
+	return err
+}
+
+// levelTokens color the level label in text mode.
+var levelTokens = []struct {
+	min   slog.Level
+	token theme.Token
+}{
+	{LevelError, theme.Danger600},
+	{LevelWarn, theme.Warning600},
+	{LevelInfo, theme.Info600},
+}
+
+func levelToken(l slog.Level) theme.Token {
+	for _, t := range levelTokens {
+		if l >= t.min {
+			return t.token
+		}
+	}
+	return theme.Slate500
+}
+
+// levelWidth is the width of the longest level name, so messages line up.
+const levelWidth = 5
+
+func (h *logHandler) text(r slog.Record, attrs []slog.Attr) string {
+	o := h.out
+	var b strings.Builder
+	label := term.PadWidth(LevelName(r.Level), levelWidth)
+	b.WriteString(o.Stylize(label, levelToken(r.Level), theme.Bold))
+	b.WriteByte(' ')
+	b.WriteString(r.Message)
+	b.WriteByte('\n')
+
+	keyWidth := 0
+	for _, a := range attrs {
+		keyWidth = max(keyWidth, term.StringWidth(a.Key))
+	}
+	indent := strings.Repeat(" ", levelWidth+1)
+	for _, a := range attrs {
+		key := term.PadWidth(a.Key, keyWidth)
+		if o.ColorsEnabled() {
+			key = o.Stylize(key, theme.Slate900, theme.Bold)
+		}
+		fmt.Fprintf(&b, "%s%s: %s\n", indent, key, a.Value.String())
+	}
+	return b.String()
+}
+
+// logObject builds the JSON line: time (when set), level, msg, then the
+// attributes with groups as nested objects.
+func logObject(r slog.Record, attrs []slog.Attr) Object {
+	obj := make(Object, 0, len(attrs)+3)
+	if !r.Time.IsZero() {
+		obj = append(obj, Field{Key: "time", Value: r.Time.Format(time.RFC3339Nano)})
+	}
+	obj = append(obj,
+		Field{Key: "level", Value: strings.ToLower(LevelName(r.Level))},
+		Field{Key: "msg", Value: r.Message},
+	)
+	return appendAttrs(obj, attrs)
+}
+
+func appendAttrs(obj Object, attrs []slog.Attr) Object {
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() != slog.KindGroup {
+			obj = append(obj, Field{Key: a.Key, Value: attrValue(a.Value)})
+			continue
+		}
+		if a.Key == "" {
+			obj = appendAttrs(obj, a.Value.Group())
+			continue
+		}
+		// Groups opened by WithGroup arrive once per WithAttrs call and
+		// once for the record; merge them into one object.
+		merged := false
+		for i, f := range obj {
+			if inner, ok := f.Value.(Object); ok && f.Key == a.Key {
+				obj[i].Value = appendAttrs(inner, a.Value.Group())
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			obj = append(obj, Field{Key: a.Key, Value: appendAttrs(Object{}, a.Value.Group())})
+		}
+	}
+	return obj
+}
+
+func attrValue(v slog.Value) any {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		return v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error()
+		}
+	}
+	return v.Any()
+}
+
+// qualify nests attrs under the handler's open groups.
+func (h *logHandler) qualify(attrs []slog.Attr) []slog.Attr {
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		attrs = []slog.Attr{{Key: h.groups[i], Value: slog.GroupValue(attrs...)}}
+	}
+	return attrs
+}
+
+// flattenAttrs resolves values and turns groups into dotted keys.
+func flattenAttrs(prefix string, attrs []slog.Attr) []slog.Attr {
+	var out []slog.Attr
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		key := a.Key
+		if prefix != "" && key != "" {
+			key = prefix + "." + key
+		} else if key == "" {
+			key = prefix
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			out = append(out, flattenAttrs(key, a.Value.Group())...)
+			continue
+		}
+		out = append(out, slog.Attr{Key: key, Value: a.Value})
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package output_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+func TestLogger(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	out := output.New()
+	out.Out = &stdout
+	out.Err = &stderr
+	out.LogLevel = output.VerbosityLevel(1)
+
+	log := out.Logger()
+	log.Trace("hidden")
+	log.Debug("loading %s", "config.yaml")
+	log.With("profile", "ops", "path", "/tmp/my dir").Warn("slow")
+
+	slog.New(out.LogHandler()).WithGroup("http").Error("failed", "status", 502)
+
+	want := "DEBUG loading config.yaml\n" +
+		"WARN  slow\n" +
+		"      profile: ops\n" +
+		"      path   : /tmp/my dir\n" +
+		"ERROR failed\n" +
+		"      http.status: 502\n"
+	if got := stderr.String(); got != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", got, want)
+	}
+	if stdout.Len() != 0 {
+		t.Fatalf("logs leaked to stdout: %q", stdout.String())
+	}
+
+	// JSON mode switches to JSON lines.
+	stderr.Reset()
+	out.Format = output.JSON
+	h := out.LogHandler().WithGroup("req").WithAttrs([]slog.Attr{slog.String("id", "r1")})
+	r := slog.NewRecord(time.Time{}, output.LevelWarn, "slow", 0)
+	r.AddAttrs(slog.Duration("took", 1500*time.Millisecond), slog.Any("err", errors.New("timeout")))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	wantJSON := `{"level":"warn","msg":"slow","req":{"id":"r1","took":"1.5s","err":"timeout"}}` + "\n"
+	if got := stderr.String(); got != wantJSON {
+		t.Fatalf("got %s, want %s", got, wantJSON)
+	}
+
+	// JSON documents on stdout must not be interleaved with logs.
+	out.Err = &stdout
+	log.Error("dropped")
+	if stdout.Len() != 0 {
+		t.Fatalf("expected logs to be silenced, got %q", stdout.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if l, err := output.ParseLevel("WARNING"); err != nil || l != output.LevelWarn {
+		t.Fatalf("ParseLevel(WARNING) = %v, %v", l, err)
+	}
+	if _, err := output.ParseLevel("loud"); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if output.VerbosityLevel(2) != output.LevelTrace || output.VerbosityLevel(0) != output.LevelInfo {
+		t.Fatal("unexpected verbosity mapping")
+	}
+}
@@ -0,0 +1,40 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Field is one key/value pair of an Object.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Object is a JSON/YAML object that keeps its keys in insertion order,
+// e.g. a table row keyed by its headers.
+type Object []Field
+
+// MarshalJSON encodes the fields in order.
+func (obj Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range obj {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.Key)
+		if err != nil {
+			return nil, err
+		}
+		val, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
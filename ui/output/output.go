@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"sync"
 
@@ -12,13 +13,38 @@ import (
 )
 
 type Output struct {
-	mu sync.Mutex
+	mu   sync.Mutex
+	live liveSet
 
 	Out io.Writer
 	Err io.Writer
 
 	Format    Format
 	ColorMode term.ColorMode
+
+	// Expression is the template or JSONPath used by those formats.
+	Expression string
+
+	// Verbosity is 0 by default and grows with each --verbose.
+	Verbosity int
+
+	// LogLevel is the lowest level the logger writes; info by default.
+	LogLevel slog.Level
+
+	// Columns selects and orders the columns of struct tables (--columns).
+	// It is applied by the component that renders the structs, before
+	// Query and Fields see the result, and rejects unknown columns where
+	// Fields yields null for them.
+	Columns []string
+
+	// Query (--query) and Fields (--fields) reshape structured output
+	// before it is encoded; see Filter. Set Query with SetQuery so it is
+	// compiled once. Either one makes streamed output wait for every row.
+	Query  string
+	Fields []string
+
+	// query is Query compiled by SetQuery
+	query *Query
 }
 
 func New() *Output {
@@ -53,6 +79,11 @@ func (o *Output) Errorf(format string, args ...any) {
 	fmt.Fprintf(o.Err, format+"\n", args...)
 }
 
+// Error writes a failure line, "[✗] message", on Err.
+func (o *Output) Error(format string, args ...any) {
+	o.Errorf("%s %s", o.Stylize("[✗]", theme.Danger600), fmt.Sprintf(format, args...))
+}
+
 func (o *Output) PrintJSON(v any) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
@@ -0,0 +1,1118 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Query is a compiled JMESPath-like expression, as accepted by --query.
+//
+// Supported: fields (a.b, "quoted key"), index and slices ([0], [-1], [1:3]),
+// projections (items[*].name, *.name, flatten []), filters
+// ([?state == 'failed' && size > `100`]), multiselect ([a, b] and
+// {name: a, size: b}), pipes (a | b), the current node (@), literals
+// ('raw', `json`, numbers) and the functions length, keys, values, contains,
+// starts_with, ends_with, sort, join and to_string.
+type Query struct {
+	expr string
+	root qNode
+}
+
+// ParseQuery compiles expr.
+func ParseQuery(expr string) (*Query, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	p := &qParser{tokens: tokens}
+	node, err := p.expression(0)
+	if err == nil && p.peek().kind != qEOF {
+		err = fmt.Errorf("unexpected %s", p.peek())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("query %q: %w", expr, err)
+	}
+	return &Query{expr: expr, root: node}, nil
+}
+
+// Apply evaluates the query against v after a round trip through
+// encoding/json. Objects in the result keep their key order.
+func (q *Query) Apply(v any) (any, error) {
+	data, err := roundTrip(v, true)
+	if err != nil {
+		return nil, err
+	}
+	return q.root.eval(data)
+}
+
+// SelectFields keeps the named keys of an object, or of every object in a
+// list, in the order given. A dotted name reaches into nested objects;
+// names match case-insensitively when there is no exact key, so "name"
+// selects a table's "Name" column. Missing keys are null.
+func SelectFields(v any, fields []string) (any, error) {
+	data, err := roundTrip(v, true)
+	if err != nil {
+		return nil, err
+	}
+	if list, ok := data.([]any); ok {
+		out := make([]any, len(list))
+		for i, item := range list {
+			out[i] = selectFields(item, fields)
+		}
+		return out, nil
+	}
+	return selectFields(data, fields), nil
+}
+
+func selectFields(v any, fields []string) any {
+	if _, ok := v.(Object); !ok {
+		return v
+	}
+	out := make(Object, 0, len(fields))
+	for _, f := range fields {
+		key, value := f, v
+		for _, part := range strings.Split(f, ".") {
+			k, val, _ := lookupKey(value, part)
+			key, value = k, val
+		}
+		if !strings.Contains(f, ".") {
+			f = key
+		}
+		out = append(out, Field{Key: f, Value: value})
+	}
+	return out
+}
+
+// lookupKey finds name in an Object, falling back to a case-insensitive
+// match, and returns the key as stored.
+func lookupKey(v any, name string) (string, any, bool) {
+	obj, ok := v.(Object)
+	if !ok {
+		return name, nil, false
+	}
+	for _, f := range obj {
+		if f.Key == name {
+			return f.Key, f.Value, true
+		}
+	}
+	for _, f := range obj {
+		if strings.EqualFold(f.Key, name) {
+			return f.Key, f.Value, true
+		}
+	}
+	return name, nil, false
+}
+
+// roundTrip passes v through encoding/json so queries, templates and paths
+// see json names, with numbers kept exact as json.Number. Objects decode as
+// Object when ordered is set, so key order survives, and as maps otherwise,
+// which templates need for .field access.
+func roundTrip(v any, ordered bool) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if ordered {
+		return decodeOrdered(dec)
+	}
+	var out any
+	err = dec.Decode(&out)
+	return out, err
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := Object{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj = append(obj, Field{Key: key.(string), Value: value})
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// Lexer
+
+type qKind int
+
+const (
+	qEOF qKind = iota
+	qIdent
+	qQuoted
+	qRaw
+	qLiteral
+	qNumber
+	qDot
+	qStar
+	qLBracket
+	qRBracket
+	qFilter
+	qFlatten
+	qLBrace
+	qRBrace
+	qLParen
+	qRParen
+	qComma
+	qColon
+	qPipe
+	qOr
+	qAnd
+	qNot
+	qCompare
+	qCurrent
+)
+
+type qToken struct {
+	kind  qKind
+	text  string
+	value any
+}
+
+func (t qToken) String() string {
+	if t.kind == qEOF {
+		return "end of query"
+	}
+	return strconv.Quote(t.text)
+}
+
+var qSymbols = []struct {
+	text string
+	kind qKind
+}{
+	{"[?", qFilter}, {"[]", qFlatten}, {"||", qOr}, {"&&", qAnd},
+	{"==", qCompare}, {"!=", qCompare}, {"<=", qCompare}, {">=", qCompare},
+	{"<", qCompare}, {">", qCompare}, {"!", qNot},
+	{".", qDot}, {"*", qStar}, {"[", qLBracket}, {"]", qRBracket},
+	{"{", qLBrace}, {"}", qRBrace}, {"(", qLParen}, {")", qRParen},
+	{",", qComma}, {":", qColon}, {"|", qPipe}, {"@", qCurrent},
+}
+
+// identRune returns the size of the rune s starts with when it may appear in
+// an identifier, where digits cannot come first, and 0 otherwise. Keys such
+// as "données" are decoded as UTF-8 rather than byte by byte.
+func identRune(s string, first bool) int {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == '_' || unicode.IsLetter(r) || !first && unicode.IsDigit(r) {
+		return size
+	}
+	return 0
+}
+
+func lexQuery(s string) ([]qToken, error) {
+	var tokens []qToken
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case identRune(s[i:], true) > 0:
+			j := i
+			for j < len(s) {
+				size := identRune(s[j:], j == i)
+				if size == 0 {
+					break
+				}
+				j += size
+			}
+			tokens = append(tokens, qToken{kind: qIdent, text: s[i:j]})
+			i = j
+		case c == '-' || unicode.IsDigit(rune(c)):
+			j := i + 1
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			if _, err := strconv.ParseFloat(s[i:j], 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q", s[i:j])
+			}
+			tokens = append(tokens, qToken{kind: qNumber, text: s[i:j], value: json.Number(s[i:j])})
+			i = j
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(s) && s[j] != c {
+				if s[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated %c", c)
+			}
+			text := s[i : j+1]
+			tok, err := quotedToken(c, text)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, tok)
+			i = j + 1
+		default:
+			matched := false
+			for _, sym := range qSymbols {
+				if strings.HasPrefix(s[i:], sym.text) {
+					tokens = append(tokens, qToken{kind: sym.kind, text: sym.text})
+					i += len(sym.text)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q", c)
+			}
+		}
+	}
+	return append(tokens, qToken{kind: qEOF}), nil
+}
+
+func quotedToken(quote byte, text string) (qToken, error) {
+	inner := text[1 : len(text)-1]
+	switch quote {
+	case '"':
+		name, err := strconv.Unquote(text)
+		return qToken{kind: qQuoted, text: text, value: name}, err
+	case '\'':
+		return qToken{kind: qRaw, text: text, value: strings.ReplaceAll(inner, `\'`, "'")}, nil
+	}
+	dec := json.NewDecoder(strings.NewReader(inner))
+	dec.UseNumber()
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return qToken{}, fmt.Errorf("invalid literal %s: %w", text, err)
+	}
+	return qToken{kind: qLiteral, text: text, value: value}, nil
+}
+
+// Parser: a Pratt parser with JMESPath binding powers.
+
+var qBindingPower = map[qKind]int{
+	qPipe: 1, qOr: 2, qAnd: 3, qCompare: 5, qFlatten: 9, qStar: 20,
+	qFilter: 21, qDot: 40, qNot: 45, qLBrace: 50, qLBracket: 55, qLParen: 60,
+}
+
+// projectionStop is the binding power below which a token ends a projection.
+const projectionStop = 10
+
+type qParser struct {
+	tokens []qToken
+	pos    int
+}
+
+func (p *qParser) peek() qToken { return p.tokens[p.pos] }
+
+func (p *qParser) next() qToken {
+	t := p.tokens[p.pos]
+	if t.kind != qEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *qParser) expect(kind qKind, what string) error {
+	if t := p.next(); t.kind != kind {
+		return fmt.Errorf("expected %s, got %s", what, t)
+	}
+	return nil
+}
+
+func (p *qParser) expression(bp int) (qNode, error) {
+	left, err := p.nud(p.next())
+	if err != nil {
+		return nil, err
+	}
+	for bp < qBindingPower[p.peek().kind] {
+		if left, err = p.led(p.next(), left); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *qParser) nud(t qToken) (qNode, error) {
+	switch t.kind {
+	case qIdent:
+		return qField{name: t.text}, nil
+	case qQuoted:
+		return qField{name: t.value.(string)}, nil
+	case qRaw, qLiteral, qNumber:
+		return qLiteralNode{value: t.value}, nil
+	case qCurrent:
+		return qIdentity{}, nil
+	case qStar:
+		right, err := p.projectionRHS(qBindingPower[qStar])
+		return qValues{left: qIdentity{}, right: right}, err
+	case qFlatten:
+		right, err := p.projectionRHS(qBindingPower[qFlatten])
+		return qProjection{left: qFlattenNode{left: qIdentity{}}, right: right}, err
+	case qFilter:
+		return p.filter(qIdentity{})
+	case qLBracket:
+		return p.bracket(qIdentity{})
+	case qLBrace:
+		return p.hash()
+	case qNot:
+		expr, err := p.expression(qBindingPower[qNot])
+		return qNotNode{expr: expr}, err
+	case qLParen:
+		expr, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		return expr, p.expect(qRParen, ")")
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+func (p *qParser) led(t qToken, left qNode) (qNode, error) {
+	switch t.kind {
+	case qDot:
+		if p.peek().kind == qStar {
+			p.next()
+			right, err := p.projectionRHS(qBindingPower[qStar])
+			return qValues{left: left, right: right}, err
+		}
+		right, err := p.dotRHS()
+		return qSub{left: left, right: right}, err
+	case qLBracket:
+		return p.bracket(left)
+	case qFlatten:
+		right, err := p.projectionRHS(qBindingPower[qFlatten])
+		return qProjection{left: qFlattenNode{left: left}, right: right}, err
+	case qFilter:
+		return p.filter(left)
+	case qPipe:
+		right, err := p.expression(qBindingPower[qPipe])
+		return qPipeNode{left: left, right: right}, err
+	case qOr, qAnd:
+		right, err := p.expression(qBindingPower[t.kind])
+		return qLogic{op: t.kind, left: left, right: right}, err
+	case qCompare:
+		right, err := p.expression(qBindingPower[qCompare])
+		return qCompareNode{op: t.text, left: left, right: right}, err
+	case qLParen:
+		field, ok := left.(qField)
+		if !ok {
+			return nil, fmt.Errorf("unexpected (")
+		}
+		return p.call(field.name)
+	}
+	return nil, fmt.Errorf("unexpected %s", t)
+}
+
+// bracket parses what follows "[": an index, a slice, [*] or a
+// multiselect list.
+func (p *qParser) bracket(left qNode) (qNode, error) {
+	switch p.peek().kind {
+	case qNumber, qColon:
+		var parts [3]*int
+		n := 0
+		for {
+			if t := p.peek(); t.kind == qNumber {
+				p.next()
+				i, err := strconv.Atoi(t.text)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %s", t.text)
+				}
+				parts[n] = &i
+			}
+			if p.peek().kind != qColon {
+				break
+			}
+			p.next()
+			if n++; n > 2 {
+				return nil, fmt.Errorf("invalid slice")
+			}
+		}
+		if err := p.expect(qRBracket, "]"); err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return qSub{left: left, right: qIndex{index: *parts[0]}}, nil
+		}
+		right, err := p.projectionRHS(qBindingPower[qStar])
+		slice := qSlice{start: parts[0], stop: parts[1], step: parts[2]}
+		return qProjection{left: qSub{left: left, right: slice}, right: right}, err
+	case qStar:
+		p.next()
+		if err := p.expect(qRBracket, "]"); err != nil {
+			return nil, err
+		}
+		right, err := p.projectionRHS(qBindingPower[qStar])
+		return qProjection{left: left, right: right}, err
+	}
+
+	if _, ok := left.(qIdentity); !ok {
+		return nil, fmt.Errorf("expected index, slice or * after [")
+	}
+	var items []qNode
+	for {
+		item, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.peek().kind != qComma {
+			break
+		}
+		p.next()
+	}
+	return qList{items: items}, p.expect(qRBracket, "]")
+}
+
+func (p *qParser) filter(left qNode) (qNode, error) {
+	cond, err := p.expression(0)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(qRBracket, "]"); err != nil {
+		return nil, err
+	}
+	right, err := p.projectionRHS(qBindingPower[qFilter])
+	return qProjection{left: left, cond: cond, right: right}, err
+}
+
+func (p *qParser) hash() (qNode, error) {
+	var h qHash
+	for {
+		t := p.next()
+		if t.kind != qIdent && t.kind != qQuoted {
+			return nil, fmt.Errorf("expected key, got %s", t)
+		}
+		key := t.text
+		if t.kind == qQuoted {
+			key = t.value.(string)
+		}
+		if err := p.expect(qColon, ":"); err != nil {
+			return nil, err
+		}
+		value, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		h.keys = append(h.keys, key)
+		h.values = append(h.values, value)
+		if p.peek().kind != qComma {
+			break
+		}
+		p.next()
+	}
+	return h, p.expect(qRBrace, "}")
+}
+
+func (p *qParser) call(name string) (qNode, error) {
+	fn, ok := qFunctions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s()", name)
+	}
+	call := qCall{name: name, fn: fn}
+	for p.peek().kind != qRParen {
+		arg, err := p.expression(0)
+		if err != nil {
+			return nil, err
+		}
+		call.args = append(call.args, arg)
+		if p.peek().kind != qComma {
+			break
+		}
+		p.next()
+	}
+	return call, p.expect(qRParen, ")")
+}
+
+// dotRHS parses what may follow ".": a field, multiselect or function.
+func (p *qParser) dotRHS() (qNode, error) {
+	switch p.peek().kind {
+	case qIdent, qQuoted:
+		return p.expression(qBindingPower[qDot])
+	case qLBracket:
+		p.next()
+		return p.bracket(qIdentity{})
+	case qLBrace:
+		p.next()
+		return p.hash()
+	}
+	return nil, fmt.Errorf("unexpected %s after .", p.peek())
+}
+
+// projectionRHS parses the expression applied to each projected element.
+func (p *qParser) projectionRHS(bp int) (qNode, error) {
+	switch t := p.peek(); {
+	case qBindingPower[t.kind] < projectionStop:
+		return qIdentity{}, nil
+	case t.kind == qLBracket || t.kind == qFilter || t.kind == qFlatten:
+		return p.expression(bp)
+	case t.kind == qDot:
+		p.next()
+		return p.dotRHS()
+	}
+	return nil, fmt.Errorf("unexpected %s", p.peek())
+}
+
+// Evaluation
+
+type qNode interface {
+	eval(v any) (any, error)
+}
+
+type (
+	qIdentity    struct{}
+	qField       struct{ name string }
+	qLiteralNode struct{ value any }
+	qIndex       struct{ index int }
+	qSlice       struct{ start, stop, step *int }
+	qSub         struct{ left, right qNode }
+	qPipeNode    struct{ left, right qNode }
+	qValues      struct{ left, right qNode }
+	qFlattenNode struct{ left qNode }
+	qNotNode     struct{ expr qNode }
+	qList        struct{ items []qNode }
+	qHash        struct {
+		keys   []string
+		values []qNode
+	}
+	qLogic struct {
+		op          qKind
+		left, right qNode
+	}
+	qCompareNode struct {
+		op          string
+		left, right qNode
+	}
+	// qProjection maps right over the list produced by left, keeping
+	// elements that satisfy cond (when set) and dropping null results.
+	qProjection struct{ left, cond, right qNode }
+	qCall       struct {
+		name string
+		fn   func(args []any) (any, error)
+		args []qNode
+	}
+)
+
+func (qIdentity) eval(v any) (any, error)      { return v, nil }
+func (n qLiteralNode) eval(any) (any, error)   { return n.value, nil }
+func (n qField) eval(v any) (any, error)       { _, value, _ := lookupField(v, n.name); return value, nil }
+func (n qFlattenNode) eval(v any) (any, error) { return flatten(n.left, v) }
+
+func (n qIndex) eval(v any) (any, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, nil
+	}
+	i := n.index
+	if i < 0 {
+		i += len(list)
+	}
+	if i < 0 || i >= len(list) {
+		return nil, nil
+	}
+	return list[i], nil
+}
+
+func (n qSlice) eval(v any) (any, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, nil
+	}
+	step := 1
+	if n.step != nil {
+		step = *n.step
+	}
+	if step == 0 {
+		return nil, fmt.Errorf("slice step cannot be 0")
+	}
+	start, stop := 0, len(list)
+	if step < 0 {
+		start, stop = len(list)-1, -1
+	}
+	if n.start != nil {
+		start = sliceBound(*n.start, len(list), step)
+	}
+	if n.stop != nil {
+		stop = sliceBound(*n.stop, len(list), step)
+	}
+	out := []any{}
+	for i := start; (step > 0 && i < stop) || (step < 0 && i > stop); i += step {
+		out = append(out, list[i])
+	}
+	return out, nil
+}
+
+func sliceBound(i, n, step int) int {
+	if i < 0 {
+		i += n
+	}
+	if step < 0 {
+		return max(-1, min(i, n-1))
+	}
+	return max(0, min(i, n))
+}
+
+func (n qSub) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil || left == nil {
+		return nil, err
+	}
+	return n.right.eval(left)
+}
+
+func (n qPipeNode) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	return n.right.eval(left)
+}
+
+func (n qValues) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := left.(Object)
+	if !ok {
+		return nil, nil
+	}
+	values := make([]any, len(obj))
+	for i, f := range obj {
+		values[i] = f.Value
+	}
+	return project(values, nil, n.right)
+}
+
+func (n qProjection) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := left.([]any)
+	if !ok {
+		return nil, nil
+	}
+	return project(list, n.cond, n.right)
+}
+
+func project(list []any, cond, right qNode) (any, error) {
+	out := []any{}
+	for _, item := range list {
+		if cond != nil {
+			ok, err := cond.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			if !truthy(ok) {
+				continue
+			}
+		}
+		value, err := right.eval(item)
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			out = append(out, value)
+		}
+	}
+	return out, nil
+}
+
+func flatten(left qNode, v any) (any, error) {
+	value, err := left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	list, ok := value.([]any)
+	if !ok {
+		return nil, nil
+	}
+	out := []any{}
+	for _, item := range list {
+		if inner, ok := item.([]any); ok {
+			out = append(out, inner...)
+		} else {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (n qNotNode) eval(v any) (any, error) {
+	value, err := n.expr.eval(v)
+	return !truthy(value), err
+}
+
+func (n qList) eval(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	out := make([]any, len(n.items))
+	for i, item := range n.items {
+		value, err := item.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = value
+	}
+	return out, nil
+}
+
+func (n qHash) eval(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	out := make(Object, len(n.keys))
+	for i, key := range n.keys {
+		value, err := n.values[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = Field{Key: key, Value: value}
+	}
+	return out, nil
+}
+
+func (n qLogic) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	if truthy(left) == (n.op == qOr) {
+		return left, nil
+	}
+	return n.right.eval(v)
+}
+
+func (n qCompareNode) eval(v any) (any, error) {
+	left, err := n.left.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return equalJSON(left, right), nil
+	case "!=":
+		return !equalJSON(left, right), nil
+	}
+	if a, ok := numberValue(left); ok {
+		if b, ok := numberValue(right); ok {
+			return compareOrdered(a, b, n.op), nil
+		}
+	}
+	if a, ok := left.(string); ok {
+		if b, ok := right.(string); ok {
+			return compareOrdered(a, b, n.op), nil
+		}
+	}
+	return nil, nil
+}
+
+func (n qCall) eval(v any) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		value, err := arg.eval(v)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = value
+	}
+	value, err := n.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s(): %w", n.name, err)
+	}
+	return value, nil
+}
+
+// lookupField is lookupKey without the case-insensitive fallback, as in
+// JMESPath.
+func lookupField(v any, name string) (string, any, bool) {
+	if obj, ok := v.(Object); ok {
+		for _, f := range obj {
+			if f.Key == name {
+				return f.Key, f.Value, true
+			}
+		}
+	}
+	return name, nil, false
+}
+
+// truthy follows JMESPath: null, false and empty strings, lists and
+// objects are false.
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case []any:
+		return len(t) > 0
+	case Object:
+		return len(t) > 0
+	}
+	return true
+}
+
+func numberValue(v any) (float64, bool) {
+	n, ok := v.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	f, err := n.Float64()
+	return f, err == nil
+}
+
+func equalJSON(a, b any) bool {
+	if x, ok := numberValue(a); ok {
+		y, ok := numberValue(b)
+		return ok && x == y
+	}
+	switch x := a.(type) {
+	case []any:
+		y, ok := b.([]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if !equalJSON(x[i], y[i]) {
+				return false
+			}
+		}
+		return true
+	case Object:
+		y, ok := b.(Object)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for _, f := range x {
+			_, value, found := lookupField(y, f.Key)
+			if !found || !equalJSON(f.Value, value) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+var qFunctions = map[string]func(args []any) (any, error){
+	"length": func(args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		switch t := args[0].(type) {
+		case string:
+			return json.Number(strconv.Itoa(len([]rune(t)))), nil
+		case []any:
+			return json.Number(strconv.Itoa(len(t))), nil
+		case Object:
+			return json.Number(strconv.Itoa(len(t))), nil
+		}
+		return nil, fmt.Errorf("expected a string, array or object")
+	},
+	"keys": func(args []any) (any, error) {
+		obj, err := objectArg(args)
+		if err != nil {
+			return nil, err
+		}
+		keys := make([]any, len(obj))
+		for i, f := range obj {
+			keys[i] = f.Key
+		}
+		return keys, nil
+	},
+	"values": func(args []any) (any, error) {
+		obj, err := objectArg(args)
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, len(obj))
+		for i, f := range obj {
+			values[i] = f.Value
+		}
+		return values, nil
+	},
+	"contains": func(args []any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		switch t := args[0].(type) {
+		case string:
+			s, ok := args[1].(string)
+			return ok && strings.Contains(t, s), nil
+		case []any:
+			for _, item := range t {
+				if equalJSON(item, args[1]) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return nil, fmt.Errorf("expected a string or array")
+	},
+	"starts_with": func(args []any) (any, error) {
+		a, b, err := stringArgs(args)
+		return err == nil && strings.HasPrefix(a, b), err
+	},
+	"ends_with": func(args []any) (any, error) {
+		a, b, err := stringArgs(args)
+		return err == nil && strings.HasSuffix(a, b), err
+	},
+	"sort": func(args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		list, ok := args[0].([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an array")
+		}
+		// like JMESPath, only arrays of numbers or of strings sort
+		var numbers bool
+		for i, item := range list {
+			_, isNumber := numberValue(item)
+			_, isString := item.(string)
+			if i == 0 {
+				numbers = isNumber
+			}
+			if !isNumber && !isString || isNumber != numbers {
+				return nil, fmt.Errorf("expected an array of numbers or of strings")
+			}
+		}
+		out := append([]any(nil), list...)
+		sort.SliceStable(out, func(i, j int) bool {
+			if a, ok := numberValue(out[i]); ok {
+				b, _ := numberValue(out[j])
+				return a < b
+			}
+			a, _ := out[i].(string)
+			b, _ := out[j].(string)
+			return a < b
+		})
+		return out, nil
+	},
+	"join": func(args []any) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("expected 2 arguments")
+		}
+		sep, ok := args[0].(string)
+		list, ok2 := args[1].([]any)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("expected a separator and an array of strings")
+		}
+		parts := make([]string, len(list))
+		for i, item := range list {
+			parts[i] = toString(item)
+		}
+		return strings.Join(parts, sep), nil
+	},
+	"to_string": func(args []any) (any, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("expected 1 argument")
+		}
+		if s, ok := args[0].(string); ok {
+			return s, nil
+		}
+		data, err := json.Marshal(args[0])
+		return string(data), err
+	},
+}
+
+func objectArg(args []any) (Object, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument")
+	}
+	obj, ok := args[0].(Object)
+	if !ok {
+		return nil, fmt.Errorf("expected an object")
+	}
+	return obj, nil
+}
+
+func stringArgs(args []any) (string, string, error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("expected 2 arguments")
+	}
+	a, ok := args[0].(string)
+	b, ok2 := args[1].(string)
+	if !ok || !ok2 {
+		return "", "", fmt.Errorf("expected strings")
+	}
+	return a, b, nil
+}
+
+// Filtering reports whether --query or --fields reshape the output.
+func (o *Output) Filtering() bool {
+	return o.Query != "" || len(o.Fields) > 0
+}
+
+// SetQuery compiles expr and makes it the query applied by Filter; an empty
+// expr removes the query.
+func (o *Output) SetQuery(expr string) error {
+	var q *Query
+	if expr != "" {
+		var err error
+		if q, err = ParseQuery(expr); err != nil {
+			return err
+		}
+	}
+	o.Query, o.query = expr, q
+	return nil
+}
+
+// Filter applies --query, then --fields, to v and returns v unchanged when
+// neither is set. Print calls it for every format.
+//
+// Both work on the whole value, so streams (RecordWriter, StreamTable)
+// buffer every row until Close when either is set.
+func (o *Output) Filter(v any) (any, error) {
+	if o.Query != "" {
+		q := o.query
+		if q == nil || q.expr != o.Query {
+			// Query was assigned directly rather than through SetQuery
+			var err error
+			if q, err = ParseQuery(o.Query); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if v, err = q.Apply(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.Fields) > 0 {
+		return SelectFields(v, o.Fields)
+	}
+	return v, nil
+}
@@ -0,0 +1,132 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+var queryDoc = map[string]any{
+	"items": []map[string]any{
+		{"name": "api", "state": "running", "size": 120, "tags": []string{"web"}},
+		{"name": "db", "state": "failed", "size": 4096, "tags": []string{"data", "internal"}},
+		{"name": "cache", "state": "failed", "size": 64, "tags": []string{}},
+	},
+	"meta": map[string]any{"region": "eu-west"},
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`meta.region`, `"eu-west"`},
+		{`items[0].name`, `"api"`},
+		{`items[-1].name`, `"cache"`},
+		{`items[*].name`, `["api","db","cache"]`},
+		{`items[:2].name`, `["api","db"]`},
+		{`items[?state == 'failed'].name`, `["db","cache"]`},
+		{"items[?state == 'failed' && size > `100`].name", `["db"]`},
+		{`items[?size < 100 || name == 'api'].name`, `["api","cache"]`},
+		{`items[?!contains(tags, 'web')].name`, `["db","cache"]`},
+		{`items[*].tags[]`, `["web","data","internal"]`},
+		{`items[*].{id: name, bytes: size}`, `[{"id":"api","bytes":120},{"id":"db","bytes":4096},{"id":"cache","bytes":64}]`},
+		{`items[*].[name, state] | [1]`, `["db","failed"]`},
+		{`length(items)`, `3`},
+		{`sort(items[*].name) | join(', ', @)`, `"api, cache, db"`},
+		{`keys(meta)`, `["region"]`},
+		{`missing.field`, `null`},
+	}
+	for _, tt := range tests {
+		q, err := output.ParseQuery(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseQuery(%s): %v", tt.expr, err)
+		}
+		v, err := q.Apply(queryDoc)
+		if err != nil {
+			t.Fatalf("Apply(%s): %v", tt.expr, err)
+		}
+		got, _ := json.Marshal(v)
+		if string(got) != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.expr, got, tt.want)
+		}
+	}
+
+	for _, expr := range []string{`items[`, `items[?state ==]`, `nope(items)`, `a.`} {
+		if _, err := output.ParseQuery(expr); err == nil {
+			t.Fatalf("ParseQuery(%s): expected error", expr)
+		}
+	}
+
+	for _, expr := range []string{`sort(items)`, `sort(items[*].[name, size][])`} {
+		q, err := output.ParseQuery(expr)
+		if err != nil {
+			t.Fatalf("ParseQuery(%s): %v", expr, err)
+		}
+		if _, err := q.Apply(queryDoc); err == nil {
+			t.Errorf("Apply(%s): expected an error for an array that is not all numbers or all strings", expr)
+		}
+	}
+
+	if err := output.New().SetQuery(`items[`); err == nil {
+		t.Error("SetQuery: expected a parse error")
+	}
+}
+
+func TestQueryUnicodeKeys(t *testing.T) {
+	doc := map[string]any{
+		"données": map[string]any{"名前": "api", "é2": true},
+	}
+	for expr, want := range map[string]string{
+		`données.名前`:          `"api"`,
+		`données.é2`:          `true`,
+		`données.{nom: 名前}`:   `{"nom":"api"}`,
+		`keys(données) | [0]`: `"é2"`,
+	} {
+		q, err := output.ParseQuery(expr)
+		if err != nil {
+			t.Fatalf("ParseQuery(%s): %v", expr, err)
+		}
+		v, err := q.Apply(doc)
+		if err != nil {
+			t.Fatalf("Apply(%s): %v", expr, err)
+		}
+		if got, _ := json.Marshal(v); string(got) != want {
+			t.Errorf("%s: got %s, want %s", expr, got, want)
+		}
+	}
+}
+
+func TestPrintAppliesQueryAndFields(t *testing.T) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	out.Format = output.CSV
+	if err := out.SetQuery(`items[?state == 'failed']`); err != nil {
+		t.Fatal(err)
+	}
+	out.Fields = []string{"name", "size"}
+
+	if err := out.Print(queryDoc); err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+	if got, want := buf.String(), "name,size\ndb,4096\ncache,64\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	buf.Reset()
+	out.Format = output.JSON
+	if err := out.SetQuery(""); err != nil {
+		t.Fatal(err)
+	}
+	out.Fields = []string{"state", "Name"}
+	if err := out.PrintRecords([]string{"Name", "State"}, [][]string{{"api", "ok"}}); err != nil {
+		t.Fatalf("PrintRecords: %v", err)
+	}
+	want := "[\n  {\n    \"State\": \"ok\",\n    \"Name\": \"api\"\n  }\n]\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
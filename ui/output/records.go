@@ -0,0 +1,288 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/ikaitla/framework/ui/term"
+	"gopkg.in/yaml.v3"
+)
+
+// Tabular reports whether f renders rows and columns rather than documents.
+func (f Format) Tabular() bool {
+	switch f {
+	case CSV, TSV, Markdown, HTML:
+		return true
+	}
+	return false
+}
+
+// PrintRecords writes a header row and data rows in the selected Format.
+// Tabular formats write the rows directly; JSON and YAML get an array of
+// objects keyed by header. Text is left to the caller, which knows how it
+// wants columns laid out, and falls back to the same objects. With --query
+// or --fields the rows go through Print as objects.
+func (o *Output) PrintRecords(headers []string, rows [][]string) error {
+	if !o.Format.Tabular() || o.Filtering() {
+		return o.Print(Records(plainRecords(headers, rows)))
+	}
+	return o.writeRecords(headers, rows)
+}
+
+func (o *Output) writeRecords(headers []string, rows [][]string) error {
+	if len(headers) == 0 {
+		return nil
+	}
+	headers, rows = plainRecords(headers, rows)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return writeRecords(o.Out, o.Format, headers, rows)
+}
+
+// Records converts rows into objects keyed by header. A repeated header
+// gets a numeric suffix so no key is lost.
+func Records(headers []string, rows [][]string) []Object {
+	keys := uniqueKeys(headers)
+	objs := make([]Object, 0, len(rows))
+	for _, row := range rows {
+		obj := make(Object, len(keys))
+		for i, h := range keys {
+			var v string
+			if i < len(row) {
+				v = row[i]
+			}
+			obj[i] = Field{Key: h, Value: v}
+		}
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// uniqueKeys suffixes repeated headers so they stay distinct object keys:
+// "Name", "Name" becomes "Name", "Name_2".
+func uniqueKeys(headers []string) []string {
+	seen := make(map[string]bool, len(headers))
+	for _, h := range headers {
+		seen[h] = true
+	}
+	keys := make([]string, len(headers))
+	used := make(map[string]bool, len(headers))
+	for i, h := range headers {
+		key := h
+		for n := 2; used[key] || key != h && seen[key]; n++ {
+			key = fmt.Sprintf("%s_%d", h, n)
+		}
+		used[key] = true
+		keys[i] = key
+	}
+	return keys
+}
+
+func writeRecords(w io.Writer, f Format, headers []string, rows [][]string) error {
+	switch f {
+	case CSV:
+		return writeDelimited(w, ',', headers, rows)
+	case TSV:
+		return writeDelimited(w, '\t', headers, rows)
+	case Markdown:
+		return writeMarkdown(w, headers, rows)
+	case HTML:
+		return writeHTML(w, headers, rows)
+	}
+	return fmt.Errorf("output format %q is not tabular", f)
+}
+
+// writeDelimited follows RFC 4180: fields holding the separator, quotes or
+// line breaks are quoted and inner quotes doubled.
+func writeDelimited(w io.Writer, sep rune, headers []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = sep
+	if err := cw.Write(headers); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(fitRow(row, len(headers))); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeMarkdown writes a GitHub-flavored Markdown table.
+func writeMarkdown(w io.Writer, headers []string, rows [][]string) error {
+	var b strings.Builder
+	b.WriteString(markdownHead(headers))
+	for _, row := range rows {
+		b.WriteString(markdownRow(fitRow(row, len(headers))))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func markdownHead(headers []string) string {
+	seps := make([]string, len(headers))
+	for i := range seps {
+		seps[i] = "---"
+	}
+	return markdownRow(headers) + "| " + strings.Join(seps, " | ") + " |\n"
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, c := range cells {
+		escaped[i] = EscapeMarkdown(c)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |\n"
+}
+
+// EscapeMarkdown makes s safe inside a Markdown table cell: backslashes and
+// pipes are escaped and line breaks become <br>.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// writeHTML writes a minimal, class-free HTML table.
+func writeHTML(w io.Writer, headers []string, rows [][]string) error {
+	var b strings.Builder
+	b.WriteString(htmlHead(headers))
+	for _, row := range rows {
+		b.WriteString(htmlRow(fitRow(row, len(headers))))
+	}
+	b.WriteString(htmlTail)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+const htmlTail = "  </tbody>\n</table>\n"
+
+func htmlHead(headers []string) string {
+	var b strings.Builder
+	b.WriteString("<table>\n  <thead>\n    <tr>")
+	for _, h := range headers {
+		b.WriteString("<th>" + html.EscapeString(h) + "</th>")
+	}
+	b.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+	return b.String()
+}
+
+func htmlRow(cells []string) string {
+	var b strings.Builder
+	b.WriteString("    <tr>")
+	for _, c := range cells {
+		b.WriteString("<td>" + html.EscapeString(c) + "</td>")
+	}
+	b.WriteString("</tr>\n")
+	return b.String()
+}
+
+// plainRecords strips escape sequences left by Output.Stylize, which mean
+// nothing outside a terminal.
+func plainRecords(headers []string, rows [][]string) ([]string, [][]string) {
+	clean := func(cells []string) []string {
+		out := make([]string, len(cells))
+		for i, c := range cells {
+			out[i] = term.StripANSI(c)
+		}
+		return out
+	}
+	plain := make([][]string, len(rows))
+	for i, row := range rows {
+		plain[i] = clean(row)
+	}
+	return clean(headers), plain
+}
+
+func fitRow(row []string, n int) []string {
+	if len(row) == n {
+		return row
+	}
+	out := make([]string, n)
+	copy(out, row)
+	return out
+}
+
+// Tabulate turns an arbitrary value into headers and rows: an array of
+// objects becomes one row per object, a single object becomes one row, and
+// an array of scalars becomes a single "value" column.
+func Tabulate(v any) ([]string, [][]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil, nil
+	}
+
+	root := doc.Content[0]
+	items := []*yaml.Node{root}
+	if root.Kind == yaml.SequenceNode {
+		items = root.Content
+	}
+
+	var headers []string
+	index := map[string]int{}
+	var rows []map[string]string
+	for _, item := range items {
+		row := map[string]string{}
+		switch item.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				key := item.Content[i].Value
+				if _, ok := index[key]; !ok {
+					index[key] = len(headers)
+					headers = append(headers, key)
+				}
+				row[key] = cellValue(item.Content[i+1])
+			}
+		case yaml.ScalarNode:
+			if _, ok := index["value"]; !ok {
+				index["value"] = len(headers)
+				headers = append(headers, "value")
+			}
+			row["value"] = cellValue(item)
+		default:
+			return nil, nil, fmt.Errorf("cannot render nested arrays as a table")
+		}
+		rows = append(rows, row)
+	}
+
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = make([]string, len(headers))
+		for j, h := range headers {
+			out[i][j] = row[h]
+		}
+	}
+	return headers, out, nil
+}
+
+// cellValue renders a scalar as-is and nested values as compact JSON.
+func cellValue(n *yaml.Node) string {
+	if n.Kind == yaml.ScalarNode {
+		if n.Tag == "!!null" {
+			return ""
+		}
+		return n.Value
+	}
+	var v any
+	if err := n.Decode(&v); err != nil {
+		return ""
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,68 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+func TestPrintRecords(t *testing.T) {
+	headers := []string{"Name", "Note"}
+	rows := [][]string{
+		{"ikaitla", `says "hi", twice`},
+		{"a|b", "line1\nline2"},
+		{`c:\`, "x"},
+	}
+
+	tests := []struct {
+		format output.Format
+		want   string
+	}{
+		{output.CSV, "Name,Note\nikaitla,\"says \"\"hi\"\", twice\"\na|b,\"line1\nline2\"\nc:\\,x\n"},
+		{output.TSV, "Name\tNote\nikaitla\t\"says \"\"hi\"\", twice\"\na|b\t\"line1\nline2\"\nc:\\\tx\n"},
+		{output.Markdown, "| Name | Note |\n| --- | --- |\n| ikaitla | says \"hi\", twice |\n| a\\|b | line1<br>line2 |\n| c:\\\\ | x |\n"},
+		{output.HTML, "<table>\n  <thead>\n    <tr><th>Name</th><th>Note</th></tr>\n  </thead>\n  <tbody>\n" +
+			"    <tr><td>ikaitla</td><td>says &#34;hi&#34;, twice</td></tr>\n" +
+			"    <tr><td>a|b</td><td>line1\nline2</td></tr>\n" +
+			"    <tr><td>c:\\</td><td>x</td></tr>\n  </tbody>\n</table>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			out := output.New()
+			out.Out = &buf
+			out.Format = tt.format
+
+			if err := out.PrintRecords(headers, rows); err != nil {
+				t.Fatalf("PrintRecords: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Fatalf("got:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint_TabularFromStructs(t *testing.T) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	out.Format = output.CSV
+
+	type item struct {
+		Name  string   `json:"name"`
+		Count int      `json:"count"`
+		Tags  []string `json:"tags"`
+	}
+	err := out.Print([]item{{"a", 1, []string{"x"}}, {"b", 2, nil}})
+	if err != nil {
+		t.Fatalf("Print: %v", err)
+	}
+
+	want := "name,count,tags\na,1,\"[\"\"x\"\"]\"\nb,2,\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("got:\n%q\nwant:\n%q", got, want)
+	}
+}
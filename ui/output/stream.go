@@ -0,0 +1,164 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RecordWriter streams rows as they are produced instead of buffering a
+// whole table. JSON becomes newline-delimited objects, YAML a list written
+// item by item, tabular formats write their header once, and templates and
+// JSONPath expressions are applied to each row. --query and --fields need
+// every row, so a filtered stream is buffered and printed by Close.
+type RecordWriter struct {
+	out     *Output
+	headers []string
+	started bool
+	closed  bool
+	csv     *csv.Writer
+
+	// expr is compiled once for the template and jsonpath formats;
+	// exprErr is reported by the first Write or Close.
+	expr    expression
+	exprErr error
+
+	buffered [][]string
+}
+
+// NewRecordWriter starts a stream of rows in o.Format. Text is rendered by
+// the caller and is written as YAML items here.
+func (o *Output) NewRecordWriter(headers ...string) *RecordWriter {
+	w := &RecordWriter{out: o, headers: headers}
+	if (o.Format == Template || o.Format == JSONPath) && !o.Filtering() {
+		w.expr, w.exprErr = o.compileExpression()
+	}
+	return w
+}
+
+// Write encodes one row.
+func (w *RecordWriter) Write(row []string) error {
+	o := w.out
+	if o.Filtering() {
+		w.buffered = append(w.buffered, append([]string(nil), row...))
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	headers, rows := plainRecords(w.headers, [][]string{fitRow(row, len(w.headers))})
+	row = rows[0]
+
+	if !w.started {
+		w.started = true
+		if err := w.begin(headers); err != nil {
+			return err
+		}
+	}
+
+	switch o.Format {
+	case JSON:
+		return json.NewEncoder(o.Out).Encode(Records(headers, rows)[0])
+	case CSV, TSV:
+		if err := w.csv.Write(row); err != nil {
+			return err
+		}
+		w.csv.Flush()
+		return w.csv.Error()
+	case Markdown:
+		_, err := fmt.Fprint(o.Out, markdownRow(row))
+		return err
+	case HTML:
+		_, err := fmt.Fprint(o.Out, htmlRow(row))
+		return err
+	case Template, JSONPath:
+		if w.exprErr != nil {
+			return w.exprErr
+		}
+		return writeExpression(o.Out, w.expr, Records(headers, rows)[0])
+	}
+
+	var buf bytes.Buffer
+	if err := encodeYAML(&buf, Records(headers, rows)[0]); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(o.Out, yamlListItem(buf.String()))
+	return err
+}
+
+// Close finishes the stream. A stream without rows still writes the
+// header of tabular formats. Closing twice does nothing.
+func (w *RecordWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if w.exprErr != nil {
+		return w.exprErr
+	}
+
+	o := w.out
+	if o.Filtering() {
+		return o.PrintRecords(w.headers, w.buffered)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !w.started {
+		w.started = true
+		headers, _ := plainRecords(w.headers, nil)
+		if err := w.begin(headers); err != nil {
+			return err
+		}
+		if o.Format == YAML || o.Format == Text {
+			_, err := fmt.Fprintln(o.Out, "[]")
+			return err
+		}
+	}
+	if o.Format == HTML {
+		_, err := fmt.Fprint(o.Out, htmlTail)
+		return err
+	}
+	return nil
+}
+
+func (w *RecordWriter) begin(headers []string) error {
+	o := w.out
+	switch o.Format {
+	case CSV, TSV:
+		w.csv = csv.NewWriter(o.Out)
+		if o.Format == TSV {
+			w.csv.Comma = '\t'
+		}
+		if err := w.csv.Write(headers); err != nil {
+			return err
+		}
+		w.csv.Flush()
+		return w.csv.Error()
+	case Markdown:
+		_, err := fmt.Fprint(o.Out, markdownHead(headers))
+		return err
+	case HTML:
+		_, err := fmt.Fprint(o.Out, htmlHead(headers))
+		return err
+	}
+	return nil
+}
+
+// yamlListItem indents a YAML mapping document as one item of a list.
+func yamlListItem(doc string) string {
+	lines := strings.Split(strings.TrimRight(doc, "\n"), "\n")
+	var b strings.Builder
+	for i, line := range lines {
+		if i == 0 {
+			b.WriteString("- ")
+		} else {
+			b.WriteString("  ")
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/output"
+)
+
+func TestRecordWriterCloseTwice(t *testing.T) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	out.Format = output.HTML
+
+	w := out.NewRecordWriter("Name")
+	if err := w.Write([]string{"api"}); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(buf.String(), "</table>"); n != 1 {
+		t.Errorf("closing tags written %d times:\n%s", n, buf.String())
+	}
+}
+
+func TestRecordWriterCompilesOnce(t *testing.T) {
+	var buf bytes.Buffer
+	out := output.New()
+	out.Out = &buf
+	if err := out.SetFormatSpec("template={{.Name}}"); err != nil {
+		t.Fatal(err)
+	}
+
+	w := out.NewRecordWriter("Name")
+	out.Expression = "{{.Other}}" // compiled at creation, so not seen
+	for _, name := range []string{"api", "db"} {
+		if err := w.Write([]string{name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "api\ndb\n" {
+		t.Errorf("got %q", got)
+	}
+
+	out.Expression = "{{.Name"
+	w = out.NewRecordWriter("Name")
+	if err := w.Write([]string{"api"}); err == nil {
+		t.Error("expected the template error on Write")
+	}
+}
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ikaitla/framework/ui/term"
+	"github.com/ikaitla/framework/ui/theme"
+)
+
+// SetFormatSpec selects the format from a --output value, compiling the
+// expression of template and jsonpath formats so mistakes surface before
+// a command runs.
+func (o *Output) SetFormatSpec(spec string) error {
+	f, expr, err := ParseFormatSpec(spec)
+	if err != nil {
+		return err
+	}
+	switch f {
+	case Template:
+		_, err = o.parseTemplate(expr)
+	case JSONPath:
+		_, err = ParseJSONPath(expr)
+	}
+	if err != nil {
+		return err
+	}
+	o.Format, o.Expression = f, expr
+	return nil
+}
+
+// TemplateFuncs are available in -o template expressions and to commands
+// that render their own templates:
+//
+//	color TOKEN VALUE   style with a theme token, e.g. {{color "danger-600" .status}}
+//	bytes VALUE         1536 -> "1.5 KiB"
+//	duration VALUE      nanoseconds or "90s" -> "1m30s"
+//	join SEP LIST       {{join ", " .aliases}}
+//	pad WIDTH VALUE     pad on the right to WIDTH columns
+//	padLeft WIDTH VALUE pad on the left to WIDTH columns
+//	upper, lower        change case
+//	json VALUE          compact JSON
+//
+// Value arguments come last so they work in pipelines: {{.size | bytes}}.
+func (o *Output) TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"color": func(token string, v any) string {
+			return o.Stylize(toString(v), theme.Token(token))
+		},
+		"bytes": func(v any) (string, error) {
+			n, err := toFloat(v)
+			if err != nil {
+				return "", fmt.Errorf("bytes: %w", err)
+			}
+			return HumanBytes(int64(n)), nil
+		},
+		"duration": func(v any) (string, error) {
+			if s, ok := v.(string); ok {
+				d, err := time.ParseDuration(s)
+				if err != nil {
+					return "", fmt.Errorf("duration: %w", err)
+				}
+				return HumanDuration(d), nil
+			}
+			n, err := toFloat(v)
+			if err != nil {
+				return "", fmt.Errorf("duration: %w", err)
+			}
+			return HumanDuration(time.Duration(n)), nil
+		},
+		"join": func(sep string, v any) string {
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return toString(v)
+			}
+			parts := make([]string, rv.Len())
+			for i := range parts {
+				parts[i] = toString(rv.Index(i).Interface())
+			}
+			return strings.Join(parts, sep)
+		},
+		"pad": func(width int, v any) string {
+			return term.PadWidth(toString(v), width)
+		},
+		"padLeft": func(width int, v any) string {
+			s := toString(v)
+			if w := term.StringWidth(s); w < width {
+				return strings.Repeat(" ", width-w) + s
+			}
+			return s
+		},
+		"upper": func(v any) string { return strings.ToUpper(toString(v)) },
+		"lower": func(v any) string { return strings.ToLower(toString(v)) },
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}
+}
+
+func (o *Output) parseTemplate(expr string) (*template.Template, error) {
+	t, err := template.New("output").Funcs(o.TemplateFuncs()).Parse(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return t, nil
+}
+
+// printExpression renders v with the template or JSONPath in Expression.
+// A template runs once per element when v is a list, so {{.name}} prints
+// one name per line; a JSONPath sees the whole value, as with kubectl.
+// Output always ends with a newline.
+func (o *Output) printExpression(v any) error {
+	data, err := roundTrip(v, false)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	switch o.Format {
+	case Template:
+		t, err := o.parseTemplate(o.Expression)
+		if err != nil {
+			return err
+		}
+		items, ok := data.([]any)
+		if !ok {
+			items = []any{data}
+		}
+		for _, item := range items {
+			if err := t.Execute(&buf, item); err != nil {
+				return err
+			}
+			endLine(&buf)
+		}
+	case JSONPath:
+		p, err := ParseJSONPath(o.Expression)
+		if err != nil {
+			return err
+		}
+		if err := p.Execute(&buf, data); err != nil {
+			return err
+		}
+		endLine(&buf)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	_, err = o.Out.Write(buf.Bytes())
+	return err
+}
+
+// expression is a compiled template or JSONPath.
+type expression interface {
+	Execute(w io.Writer, data any) error
+}
+
+// compileExpression parses Expression for the template or jsonpath format.
+func (o *Output) compileExpression() (expression, error) {
+	if o.Format == Template {
+		t, err := o.parseTemplate(o.Expression)
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+	p, err := ParseJSONPath(o.Expression)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// writeExpression renders one streamed record; see RecordWriter.
+func writeExpression(w io.Writer, expr expression, record Object) error {
+	data, err := roundTrip(record, false)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := expr.Execute(&buf, data); err != nil {
+		return err
+	}
+	endLine(&buf)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func endLine(buf *bytes.Buffer) {
+	if buf.Len() > 0 && buf.Bytes()[buf.Len()-1] != '\n' {
+		buf.WriteByte('\n')
+	}
+}
+
+func toString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	}
+	return fmt.Sprint(v)
+}
+
+func toFloat(v any) (float64, error) {
+	switch t := v.(type) {
+	case json.Number:
+		return t.Float64()
+	case string:
+		return strconv.ParseFloat(t, 64)
+	case float64:
+		return t, nil
+	case float32:
+		return float64(t), nil
+	case int:
+		return float64(t), nil
+	case int64:
+		return float64(t), nil
+	case uint64:
+		return float64(t), nil
+	}
+	return 0, fmt.Errorf("not a number: %v", v)
+}
@@ -3,6 +3,7 @@ package term
 import (
 	"os"
 	"runtime"
+	"strings"
 )
 
 type ColorMode int
@@ -30,13 +31,21 @@ func SupportsColor() bool {
 	}
 
 	// Must be a TTY
-	fi, err := os.Stdout.Stat()
-	if err != nil {
-		return false
+	return IsTerminal(os.Stdout)
+}
+
+// SupportsUnicode reports whether the terminal is expected to display
+// box-drawing characters, based on the locale environment.
+func SupportsUnicode() bool {
+	if runtime.GOOS == "windows" {
+		// Windows Terminal and recent consoles render UTF-8 fine.
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") != ""
 	}
-	if (fi.Mode() & os.ModeCharDevice) == 0 {
-		return false
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if v := os.Getenv(key); v != "" {
+			v = strings.ToLower(v)
+			return strings.Contains(v, "utf-8") || strings.Contains(v, "utf8")
+		}
 	}
-
-	return true
+	return false
 }
@@ -0,0 +1,33 @@
+package term_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/ikaitla/framework/ui/term"
+)
+
+func TestSupportsUnicodeLocalePrecedence(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the locale is not used on Windows")
+	}
+	tests := []struct {
+		lcAll, lcCtype, lang string
+		want                 bool
+	}{
+		{"", "", "en_US.UTF-8", true},
+		{"", "C", "en_US.UTF-8", false},
+		{"", "en_US.utf8", "C", true},
+		{"C", "en_US.UTF-8", "en_US.UTF-8", false},
+		{"en_US.UTF-8", "C", "C", true},
+		{"", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Setenv("LC_ALL", tt.lcAll)
+		t.Setenv("LC_CTYPE", tt.lcCtype)
+		t.Setenv("LANG", tt.lang)
+		if got := term.SupportsUnicode(); got != tt.want {
+			t.Errorf("LC_ALL=%q LC_CTYPE=%q LANG=%q: got %v, want %v", tt.lcAll, tt.lcCtype, tt.lang, got, tt.want)
+		}
+	}
+}
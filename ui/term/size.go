@@ -0,0 +1,39 @@
+package term
+
+import (
+	"io"
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is assumed for terminals that do not report their size.
+const DefaultWidth = 80
+
+// IsTerminal reports whether w is a character device such as a TTY.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Width returns the column count of the terminal behind w, or 0 when w is
+// not a terminal (pipes and files have no width to fit). COLUMNS overrides
+// the size reported by the terminal.
+func Width(w io.Writer) int {
+	if !IsTerminal(w) {
+		return 0
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	if cols := terminalWidth(w.(*os.File).Fd()); cols > 0 {
+		return cols
+	}
+	return DefaultWidth
+}
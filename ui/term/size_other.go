@@ -0,0 +1,9 @@
+//go:build !(linux || darwin || freebsd || netbsd || dragonfly)
+
+package term
+
+// terminalWidth is not available on this platform; Width falls back to
+// COLUMNS or DefaultWidth.
+func terminalWidth(fd uintptr) int {
+	return 0
+}
@@ -0,0 +1,222 @@
+package term
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	esc = '\x1b'
+	bel = '\a'
+
+	zeroWidthJoiner = '\u200d'
+)
+
+// StripANSI removes terminal escape sequences (CSI such as colors, OSC such
+// as hyperlinks, and two-byte escapes) from s.
+func StripANSI(s string) string {
+	if strings.IndexByte(s, esc) < 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		if s[i] != esc {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		i += escapeLen(s[i:])
+	}
+	return b.String()
+}
+
+// escapeLen returns the byte length of the escape sequence starting at s[0].
+func escapeLen(s string) int {
+	if len(s) < 2 {
+		return len(s)
+	}
+	switch s[1] {
+	case '[': // CSI: parameters, then a final byte in 0x40..0x7e
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+		return len(s)
+	case ']': // OSC: terminated by BEL or ESC \
+		for i := 2; i < len(s); i++ {
+			if s[i] == bel {
+				return i + 1
+			}
+			if s[i] == esc && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+		return len(s)
+	default:
+		return 2
+	}
+}
+
+// StringWidth returns the number of terminal columns s occupies: escape
+// sequences and zero-width runes count for nothing, East Asian wide runes and
+// emoji for two, and an emoji joined with U+200D (ZWJ) counts as its first glyph.
+func StringWidth(s string) int {
+	s = StripANSI(s)
+	width := 0
+	joined := false
+	for _, r := range s {
+		if joined {
+			joined = false
+			if RuneWidth(r) > 0 {
+				continue
+			}
+		}
+		if r == zeroWidthJoiner {
+			joined = true
+			continue
+		}
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// RuneWidth returns the number of columns r occupies: 0 for control,
+// combining and format runes, 2 for wide runes, 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r == utf8.RuneError:
+		return 1
+	case r < 0x20 || (r >= 0x7f && r < 0xa0):
+		return 0
+	case r < 0x300:
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case isWide(r):
+		return 2
+	}
+	return 1
+}
+
+// TruncateWidth cuts s to at most width columns, ending with tail when
+// anything was removed. Escape sequences are dropped from cut strings.
+func TruncateWidth(s string, width int, tail string) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	limit := width - StringWidth(tail)
+	if limit < 0 {
+		limit, tail = width, ""
+	}
+
+	var b strings.Builder
+	used := 0
+	for _, r := range StripANSI(s) {
+		w := RuneWidth(r)
+		if used+w > limit {
+			break
+		}
+		b.WriteRune(r)
+		used += w
+	}
+	if tail == "" {
+		return b.String()
+	}
+	return strings.TrimRight(b.String(), " ") + tail
+}
+
+// WrapWidth splits s into lines of at most width columns, breaking at
+// spaces where possible and inside words that are longer than a line.
+// Existing line breaks are kept. Escape sequences are dropped.
+func WrapWidth(s string, width int) []string {
+	if width <= 0 {
+		return []string{s}
+	}
+
+	var lines []string
+	for _, para := range strings.Split(StripANSI(s), "\n") {
+		var line strings.Builder
+		lineWidth := 0
+		flush := func() {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+
+		for _, word := range strings.Fields(para) {
+			ww := StringWidth(word)
+			if lineWidth > 0 && lineWidth+1+ww <= width {
+				line.WriteByte(' ')
+				line.WriteString(word)
+				lineWidth += 1 + ww
+				continue
+			}
+			if lineWidth > 0 {
+				flush()
+			}
+			for ww > width {
+				head := TruncateWidth(word, width, "")
+				if head == "" {
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				lines = append(lines, head)
+				word = word[len(head):]
+				ww = StringWidth(word)
+			}
+			line.WriteString(word)
+			lineWidth = ww
+		}
+		flush()
+	}
+	return lines
+}
+
+// PadWidth pads s with spaces on the right up to width columns.
+func PadWidth(s string, width int) string {
+	if w := StringWidth(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
+
+func isWide(r rune) bool {
+	if r < wideRanges[0][0] {
+		return false
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	return i < len(wideRanges) && wideRanges[i][0] <= r
+}
+
+// wideRanges are the East Asian Wide (W) and Fullwidth (F) ranges, including
+// emoji presentation characters, sorted and non-overlapping.
+var wideRanges = [][2]rune{
+	{0x1100, 0x115f}, {0x231a, 0x231b}, {0x2329, 0x232a}, {0x23e9, 0x23ec},
+	{0x23f0, 0x23f0}, {0x23f3, 0x23f3}, {0x25fd, 0x25fe}, {0x2614, 0x2615},
+	{0x2648, 0x2653}, {0x267f, 0x267f}, {0x2693, 0x2693}, {0x26a1, 0x26a1},
+	{0x26aa, 0x26ab}, {0x26bd, 0x26be}, {0x26c4, 0x26c5}, {0x26ce, 0x26ce},
+	{0x26d4, 0x26d4}, {0x26ea, 0x26ea}, {0x26f2, 0x26f3}, {0x26f5, 0x26f5},
+	{0x26fa, 0x26fa}, {0x26fd, 0x26fd}, {0x2705, 0x2705}, {0x270a, 0x270b},
+	{0x2728, 0x2728}, {0x274c, 0x274c}, {0x274e, 0x274e}, {0x2753, 0x2755},
+	{0x2757, 0x2757}, {0x2795, 0x2797}, {0x27b0, 0x27b0}, {0x27bf, 0x27bf},
+	{0x2b1b, 0x2b1c}, {0x2b50, 0x2b50}, {0x2b55, 0x2b55}, {0x2e80, 0x303e},
+	{0x3041, 0x33ff}, {0x3400, 0x4dbf}, {0x4e00, 0x9fff}, {0xa000, 0xa4cf},
+	{0xa960, 0xa97f}, {0xac00, 0xd7a3}, {0xf900, 0xfaff}, {0xfe10, 0xfe19},
+	{0xfe30, 0xfe6f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x16fe0, 0x16fe4},
+	{0x17000, 0x18cff}, {0x1b000, 0x1b2ff}, {0x1f004, 0x1f004}, {0x1f0cf, 0x1f0cf},
+	{0x1f18e, 0x1f18e}, {0x1f191, 0x1f19a}, {0x1f200, 0x1f202}, {0x1f210, 0x1f23b},
+	{0x1f240, 0x1f248}, {0x1f250, 0x1f251}, {0x1f260, 0x1f265}, {0x1f300, 0x1f320},
+	{0x1f32d, 0x1f335}, {0x1f337, 0x1f37c}, {0x1f37e, 0x1f393}, {0x1f3a0, 0x1f3ca},
+	{0x1f3cf, 0x1f3d3}, {0x1f3e0, 0x1f3f0}, {0x1f3f4, 0x1f3f4}, {0x1f3f8, 0x1f43e},
+	{0x1f440, 0x1f440}, {0x1f442, 0x1f4fc}, {0x1f4ff, 0x1f53d}, {0x1f54b, 0x1f54e},
+	{0x1f550, 0x1f567}, {0x1f57a, 0x1f57a}, {0x1f595, 0x1f596}, {0x1f5a4, 0x1f5a4},
+	{0x1f5fb, 0x1f64f}, {0x1f680, 0x1f6c5}, {0x1f6cc, 0x1f6cc}, {0x1f6d0, 0x1f6d2},
+	{0x1f6d5, 0x1f6d7}, {0x1f6dc, 0x1f6df}, {0x1f6eb, 0x1f6ec}, {0x1f6f4, 0x1f6fc},
+	{0x1f7e0, 0x1f7eb}, {0x1f7f0, 0x1f7f0}, {0x1f90c, 0x1f93a}, {0x1f93c, 0x1f945},
+	{0x1f947, 0x1f9ff}, {0x1fa70, 0x1faff}, {0x20000, 0x2fffd}, {0x30000, 0x3fffd},
+}
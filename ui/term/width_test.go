@@ -0,0 +1,63 @@
+package term_test
+
+import (
+	"testing"
+
+	"github.com/ikaitla/framework/ui/term"
+)
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"hello", 5},
+		{"──", 2},
+		{"café", 4},
+		{"cafe\u0301", 4},
+		{"日本語", 6},
+		{"🚀", 2},
+		{"\U0001F469\u200d\U0001F4BB", 2},
+		{"\x1b[31mred\x1b[0m", 3},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", 4},
+	}
+	for _, tt := range tests {
+		if got := term.StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPadWidth(t *testing.T) {
+	if got := term.PadWidth("日本", 6); got != "日本  " {
+		t.Errorf("PadWidth = %q", got)
+	}
+	if got := term.PadWidth("\x1b[1mab\x1b[0m", 4); got != "\x1b[1mab\x1b[0m  " {
+		t.Errorf("PadWidth with ANSI = %q", got)
+	}
+}
+
+func TestTruncateAndWrapWidth(t *testing.T) {
+	if got := term.TruncateWidth("platform-team", 5, "…"); got != "plat…" {
+		t.Errorf("TruncateWidth = %q", got)
+	}
+	if got := term.TruncateWidth("日本語", 5, "…"); got != "日本…" {
+		t.Errorf("TruncateWidth wide = %q", got)
+	}
+
+	got := term.WrapWidth("deploy the new release", 10)
+	want := []string{"deploy the", "new", "release"}
+	if len(got) != len(want) {
+		t.Fatalf("WrapWidth = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("WrapWidth = %q, want %q", got, want)
+		}
+	}
+
+	if got := term.WrapWidth("abcdefgh", 3); len(got) != 3 || got[2] != "gh" {
+		t.Errorf("WrapWidth long word = %q", got)
+	}
+}
@@ -56,3 +56,8 @@ func ResolveANSI(t Token) string {
 		return "" // transparent / unknown
 	}
 }
+
+// Known reports whether t is part of the palette.
+func Known(t Token) bool {
+	return t == Transparent || ResolveANSI(t) != ""
+}
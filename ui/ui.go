@@ -2,6 +2,9 @@ package ui
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
+	"text/template"
 
 	"github.com/ikaitla/framework/ui/components"
 	"github.com/ikaitla/framework/ui/output"
@@ -11,12 +14,66 @@ import (
 
 var defaultOut = output.New()
 
+// SetOutput redirects standard and error output, e.g. in tests.
+func SetOutput(out, err io.Writer) {
+	defaultOut.Out = out
+	defaultOut.Err = err
+}
+
+// Stderr returns the writer used for errors and logs.
+func Stderr() io.Writer { return defaultOut.Err }
+
+// ClearLive erases running spinners and progress bars.
+func ClearLive() { defaultOut.ClearLive() }
+
 // SetFormat lets your root command wire `--output`.
 func SetFormat(f output.Format) { defaultOut.Format = f }
 
+// SetFormatSpec wires `--output` values that may carry an expression,
+// such as "template={{.name}}" or "jsonpath={.items[*].name}".
+func SetFormatSpec(spec string) error { return defaultOut.SetFormatSpec(spec) }
+
+// TemplateFuncs returns the functions available to `-o template=...`.
+func TemplateFuncs() template.FuncMap { return defaultOut.TemplateFuncs() }
+
+// Format reports the format selected with SetFormat.
+func Format() output.Format { return defaultOut.Format }
+
 // SetColorMode wires `--no-color` and/or future flags.
 func SetColorMode(m term.ColorMode) { defaultOut.ColorMode = m }
 
+// ColorMode reports the mode selected with SetColorMode.
+func ColorMode() term.ColorMode { return defaultOut.ColorMode }
+
+// SetVerbosity wires `--verbose`.
+func SetVerbosity(level int) { defaultOut.Verbosity = level }
+
+// Verbosity lets commands print extra detail only when asked.
+func Verbosity() int { return defaultOut.Verbosity }
+
+// SetLogLevel wires `--log-level` and `-v` counts to the logger.
+func SetLogLevel(level slog.Level) { defaultOut.LogLevel = level }
+
+// LogLevel reports the level set with SetLogLevel.
+func LogLevel() slog.Level { return defaultOut.LogLevel }
+
+// Log returns the leveled logger; it writes to stderr.
+func Log() *output.Logger { return defaultOut.Logger() }
+
+// LogHandler returns a slog.Handler sharing the logger's output and level:
+// themed lines in text mode, JSON lines with `-o json`. Route libraries
+// through it with slog.SetDefault(slog.New(ui.LogHandler())).
+func LogHandler() slog.Handler { return defaultOut.LogHandler() }
+
+// SetColumns wires `--columns`.
+func SetColumns(columns []string) { defaultOut.Columns = columns }
+
+// SetQuery wires `--query`, compiling it once; see output.Output.SetQuery.
+func SetQuery(query string) error { return defaultOut.SetQuery(query) }
+
+// SetFields wires `--fields`.
+func SetFields(fields []string) { defaultOut.Fields = fields }
+
 // ColorsEnabled exposes current state
 func ColorsEnabled() bool { return defaultOut.ColorsEnabled() }
 
@@ -31,6 +88,9 @@ func Print(format string, args ...any) { defaultOut.Printf(format, args...) }
 // PrintJSON matches old API
 func PrintJSON(v any) error { return defaultOut.PrintJSON(v) }
 
+// PrintValue encodes v in the format chosen with `--output`.
+func PrintValue(v any) error { return defaultOut.Print(v) }
+
 func Success(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	if defaultOut.ColorsEnabled() {
@@ -41,12 +101,7 @@ func Success(format string, args ...any) {
 }
 
 func Error(format string, args ...any) {
-	msg := fmt.Sprintf(format, args...)
-	if defaultOut.ColorsEnabled() {
-		defaultOut.Errorf("%s %s", defaultOut.Stylize("[✗]", theme.Danger600), msg)
-		return
-	}
-	defaultOut.Errorf("[✗] %s", msg)
+	defaultOut.Error(format, args...)
 }
 
 func Warning(format string, args ...any) {
@@ -58,6 +113,12 @@ func Warning(format string, args ...any) {
 	defaultOut.Printf("[!] %s", msg)
 }
 
+// Banner writes a bold warning line on stderr in the color t, e.g. to
+// flag a production context before a command runs.
+func Banner(t theme.Token, format string, args ...any) {
+	defaultOut.Errorf("%s", defaultOut.Stylize("[!] "+fmt.Sprintf(format, args...), t, theme.Bold))
+}
+
 func Info(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	if defaultOut.ColorsEnabled() {
@@ -71,6 +132,8 @@ func Info(format string, args ...any) {
 type Spinner = components.Spinner
 type ProgressBar = components.ProgressBar
 type Table = components.Table
+type Column = components.Column
+type StreamTable = components.StreamTable
 
 func NewSpinner(message string) *Spinner {
 	return components.NewSpinner(defaultOut, message)
@@ -84,6 +147,15 @@ func NewTable(headers ...string) *Table {
 	return components.NewTable(defaultOut, headers...)
 }
 
+func NewStreamTable(headers ...string) *StreamTable {
+	return components.NewStreamTable(defaultOut, headers...)
+}
+
+// RenderStructs renders a slice of tagged structs, see components.RenderStructs.
+func RenderStructs(v any, columns ...string) error {
+	return components.RenderStructs(defaultOut, v, columns...)
+}
+
 func RenderKeyValue(pairs map[string]string) {
 	components.RenderKeyValue(defaultOut, pairs)
 }